@@ -0,0 +1,134 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// idCacheTTL is the time the resolved ID of a name is cached, per kind of
+// object. Offerings, zones and OS types rarely change, while objects that
+// can be created by users are cached for a shorter time.
+var idCacheTTL = map[string]time.Duration{
+	"disk_offering":    time.Hour,
+	"network_offering": time.Hour,
+	"os_type":          time.Hour,
+	"service_offering": time.Hour,
+	"template":         10 * time.Minute,
+	"vpc_offering":     time.Hour,
+	"zone":             time.Hour,
+
+	"domain":  5 * time.Minute,
+	"project": 5 * time.Minute,
+}
+
+// idCache caches the IDs of named objects for the lifetime of a provider,
+// so resources that reference the same zone or offering by name don't all
+// have to look it up. Concurrent lookups of the same name are merged.
+type idCache struct {
+	mu      sync.Mutex
+	entries map[idCacheKey]*idCacheEntry
+}
+
+type idCacheKey struct {
+	kind  string
+	scope string
+	name  string
+}
+
+type idCacheEntry struct {
+	id      string
+	expires time.Time
+
+	// done is closed when the lookup of the entry is finished
+	done chan struct{}
+	err  error
+}
+
+func newIDCache() *idCache {
+	return &idCache{entries: make(map[idCacheKey]*idCacheEntry)}
+}
+
+// resolve returns the cached ID of the named object, or calls lookup to
+// retrieve it. Errors are not cached.
+func (c *idCache) resolve(kind, scope, name string, lookup func() (string, error)) (string, error) {
+	key := idCacheKey{kind: kind, scope: scope, name: name}
+
+	c.mu.Lock()
+	for {
+		e, ok := c.entries[key]
+		if !ok {
+			break
+		}
+		c.mu.Unlock()
+
+		<-e.done
+		if e.err == nil && time.Now().Before(e.expires) {
+			return e.id, nil
+		}
+
+		c.mu.Lock()
+		// Only remove the entry if it wasn't replaced in the meantime
+		if c.entries[key] == e {
+			delete(c.entries, key)
+		}
+	}
+
+	e := &idCacheEntry{done: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	e.id, e.err = lookup()
+	e.expires = time.Now().Add(idCacheTTL[kind])
+	close(e.done)
+
+	if e.err != nil {
+		c.mu.Lock()
+		if c.entries[key] == e {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+
+	return e.id, e.err
+}
+
+// invalidate removes all cached IDs of the given kind of object.
+func (c *idCache) invalidate(kind string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if key.kind == kind {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// invalidateIDs removes the cached IDs of the given kind of object. It is
+// called after the provider created, renamed or deleted such an object.
+func invalidateIDs(cs *providerMeta, kind string) {
+	if cs.ids != nil {
+		log.Printf("[DEBUG] Invalidating cached IDs of %s", kind)
+		cs.ids.invalidate(kind)
+	}
+}
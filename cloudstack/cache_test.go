@@ -0,0 +1,181 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIDCache(t *testing.T) {
+	c := newIDCache()
+
+	var calls int32
+	lookup := func(id string, err error) func() (string, error) {
+		return func() (string, error) {
+			atomic.AddInt32(&calls, 1)
+			return id, err
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		id, err := c.resolve("zone", "", "Sandbox-simulator", lookup("zone-1", nil))
+		if err != nil || id != "zone-1" {
+			t.Fatalf("Unexpected result: %s, %v", id, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("Expected 1 lookup, got %d", calls)
+	}
+
+	// Names are cached per kind and scope
+	c.resolve("project", "", "Sandbox-simulator", lookup("project-1", nil))
+	c.resolve("template", "zone-1", "CentOS", lookup("template-1", nil))
+	c.resolve("template", "zone-2", "CentOS", lookup("template-2", nil))
+	if calls != 4 {
+		t.Fatalf("Expected 4 lookups, got %d", calls)
+	}
+
+	if id, _ := c.resolve("template", "zone-2", "CentOS", lookup("", nil)); id != "template-2" {
+		t.Fatalf("Bad cached ID: %s", id)
+	}
+
+	// Errors are not cached
+	calls = 0
+	for i := 0; i < 2; i++ {
+		if _, err := c.resolve("network", "", "unknown", lookup("", errors.New("not found"))); err == nil {
+			t.Fatal("Expected an error")
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("Expected 2 lookups, got %d", calls)
+	}
+
+	// Invalidating a kind only removes the IDs of that kind
+	calls = 0
+	c.invalidate("template")
+	c.resolve("zone", "", "Sandbox-simulator", lookup("zone-1", nil))
+	c.resolve("template", "zone-1", "CentOS", lookup("template-1", nil))
+	if calls != 1 {
+		t.Fatalf("Expected 1 lookup, got %d", calls)
+	}
+
+	// Expired IDs are looked up again
+	calls = 0
+	idCacheTTL["test"] = time.Millisecond
+	defer delete(idCacheTTL, "test")
+
+	c.resolve("test", "", "foo", lookup("foo-1", nil))
+	time.Sleep(2 * time.Millisecond)
+	if id, _ := c.resolve("test", "", "foo", lookup("foo-2", nil)); id != "foo-2" {
+		t.Fatalf("Expected the expired ID to be looked up again, got: %s", id)
+	}
+	if calls != 2 {
+		t.Fatalf("Expected 2 lookups, got %d", calls)
+	}
+}
+
+func TestIDCache_concurrent(t *testing.T) {
+	c := newIDCache()
+
+	var calls int32
+	lookup := func() (string, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		return "zone-1", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if id, err := c.resolve("zone", "", "Sandbox-simulator", lookup); err != nil || id != "zone-1" {
+				t.Errorf("Unexpected result: %s, %v", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("Expected concurrent lookups to be merged, got %d lookups", calls)
+	}
+}
+
+func TestRetrieveID_cache(t *testing.T) {
+	s, cs := newSimulatorClient(t)
+	defer s.Close()
+
+	// Copies of the client made by withTimeout share the cache
+	for i := 0; i < 3; i++ {
+		id, e := retrieveID(withTimeout(cs, time.Minute), "zone", "Sandbox-simulator")
+		if e != nil {
+			t.Fatal(e.Error())
+		}
+		if id != s.ID("zone", "Sandbox-simulator") {
+			t.Fatalf("Bad zone ID: %s", id)
+		}
+	}
+
+	if calls := s.Calls("listZones"); calls != 1 {
+		t.Fatalf("Expected the zone to be looked up once, got %d calls", calls)
+	}
+
+	invalidateIDs(cs, "zone")
+	if _, e := retrieveID(cs, "zone", "Sandbox-simulator"); e != nil {
+		t.Fatal(e.Error())
+	}
+
+	if calls := s.Calls("listZones"); calls != 2 {
+		t.Fatalf("Expected the zone to be looked up again, got %d calls", calls)
+	}
+}
+
+func TestRetrieveID_kinds(t *testing.T) {
+	s, cs := newSimulatorClient(t)
+	defer s.Close()
+
+	cases := []struct {
+		Kind  string
+		Value string
+		ID    string
+	}{
+		{"domain", "ROOT", s.ID("domain", "ROOT")},
+		{"domain", "ROOT/tenants", s.ID("domain", "tenants")},
+		{"project", "terraform", s.ID("project", "terraform")},
+	}
+
+	for i, tc := range cases {
+		id, e := retrieveID(cs, tc.Kind, tc.Value)
+		if e != nil {
+			t.Fatalf("%d: %s", i, e.Error())
+		}
+		if id != tc.ID {
+			t.Fatalf("%d: bad ID of %s %s: %s", i, tc.Kind, tc.Value, id)
+		}
+	}
+
+	if _, e := retrieveID(cs, "unknown", "foo"); e == nil {
+		t.Fatal("Expected an error for an unknown kind")
+	}
+}
@@ -19,22 +19,241 @@
 
 package cloudstack
 
-import "github.com/apache/cloudstack-go/v2/cloudstack"
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+)
 
 // Config is the configuration structure used to instantiate a
 // new CloudStack client.
 type Config struct {
-	APIURL      string
-	APIKey      string
-	SecretKey   string
-	HTTPGETOnly bool
-	Timeout     int64
+	APIURL         string
+	APIKey         string
+	SecretKey      string
+	HTTPGETOnly    bool
+	Timeout        int64
+	CAFile         string
+	ClientCertFile string
+	ClientKeyFile  string
+	Insecure       bool
+	HTTPProxy      string
+
+	// RetryMaxAttempts is the maximum number of attempts for an API call
+	// that fails with a transient error
+	RetryMaxAttempts int
+	RetryWaitMin     time.Duration
+	RetryWaitMax     time.Duration
+	RetryErrorCodes  []int
+
+	// MaxRequestsPerSecond and MaxConcurrentRequests limit the API calls
+	// made by the client, a value of 0 disables the limit
+	MaxRequestsPerSecond  float64
+	MaxConcurrentRequests int
+
+	// ValidateReferencesAtPlan enables resolving the names of referenced
+	// objects, like zones and offerings, while planning
+	ValidateReferencesAtPlan bool
+
+	// APILogFile is the file every API call is appended to as a JSON line
+	APILogFile string
+}
+
+// requestTimeout is the deadline of a single attempt of an API call, which
+// matches the timeout of the HTTP client of the CloudStack client.
+const requestTimeout = 60 * time.Second
+
+// wrapTransport, if set, wraps the transport used to send the API calls of
+// the clients created by NewClient. The acceptance tests use it to record and
+// replay API calls.
+var wrapTransport func(c *Config, transport http.RoundTripper) http.RoundTripper
+
+// providerMeta is the meta object of the provider, which is passed to all
+// resources and data sources. It holds the CloudStack client, the
+// configuration it was created with and the cache of resolved IDs.
+type providerMeta struct {
+	*cloudstack.CloudStackClient
+
+	config *Config
+	ids    *idCache
+
+	// httpClient is shared by all clients created for the provider, so
+	// they are subject to the same rate limits
+	httpClient *http.Client
+
+	// apiLog is the API log file, which is opened once per provider
+	apiLog *os.File
 }
 
-// NewClient returns a new CloudStack client.
+// close closes the API log file of the provider, if any.
+func (m *providerMeta) close() error {
+	if m.apiLog == nil {
+		return nil
+	}
+	return m.apiLog.Close()
+}
+
+// NewClient returns a new CloudStack client. Its API calls are not written
+// to the API log file.
 func (c *Config) NewClient() (*cloudstack.CloudStackClient, error) {
-	cs := cloudstack.NewAsyncClient(c.APIURL, c.APIKey, c.SecretKey, false)
+	client, err := c.httpClient(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newClient(client, c.Timeout), nil
+}
+
+// newClient returns a new CloudStack client that sends its API calls using
+// the given HTTP client, and waits the given timeout in seconds for async
+// jobs to finish.
+func (c *Config) newClient(client *http.Client, timeout int64) *cloudstack.CloudStackClient {
+	cs := cloudstack.NewAsyncClient(
+		c.APIURL, c.APIKey, c.SecretKey, !c.Insecure, cloudstack.WithHTTPClient(client))
 	cs.HTTPGETOnly = c.HTTPGETOnly
-	cs.AsyncTimeout(c.Timeout)
-	return cs, nil
+	cs.AsyncTimeout(timeout)
+
+	return cs
+}
+
+// newMeta returns the meta object of the provider, with a new client and an
+// empty cache of resolved IDs. The API log file is opened if configured, and
+// must be closed using close.
+func (c *Config) newMeta() (*providerMeta, error) {
+	meta := &providerMeta{
+		config: c,
+		ids:    newIDCache(),
+	}
+
+	var apiLog io.Writer
+	if c.APILogFile != "" {
+		f, err := os.OpenFile(c.APILogFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("Error opening API log file %s: %s", c.APILogFile, err)
+		}
+		meta.apiLog = f
+		apiLog = f
+	}
+
+	client, err := c.httpClient(apiLog)
+	if err != nil {
+		meta.close()
+		return nil, err
+	}
+
+	meta.CloudStackClient = c.newClient(client, c.Timeout)
+	meta.httpClient = client
+
+	return meta, nil
+}
+
+// httpClient returns the HTTP client used to talk to the CloudStack API.
+// The transport settings match the defaults of the CloudStack client,
+// except for the TLS configuration, proxy, rate limits and retry policy.
+// Every attempt of an API call is logged, with secrets redacted, and also
+// written to apiLog if set.
+func (c *Config) httpClient(apiLog io.Writer) (*http.Client, error) {
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if c.HTTPProxy != "" {
+		u, err := url.Parse(c.HTTPProxy)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing HTTP proxy %s: %s", c.HTTPProxy, err)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	// The timeout is applied per attempt by the retry transport instead of
+	// on the client, so retries are not bound by the timeout of the first
+	// attempt
+	var transport http.RoundTripper = &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 60 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if wrapTransport != nil {
+		transport = wrapTransport(c, transport)
+	}
+
+	// The calls are logged below the rate limits, so the logged latency
+	// is the latency of the API itself
+	transport = &logTransport{transport: transport, out: apiLog}
+
+	if c.MaxRequestsPerSecond > 0 || c.MaxConcurrentRequests > 0 {
+		rt := &rateLimitTransport{transport: transport}
+		if c.MaxRequestsPerSecond > 0 {
+			rt.limiter = newTokenBucket(c.MaxRequestsPerSecond)
+		}
+		if c.MaxConcurrentRequests > 0 {
+			rt.sem = make(chan struct{}, c.MaxConcurrentRequests)
+		}
+		transport = rt
+	}
+
+	// Every attempt of a retried request is subject to the rate limits
+	transport = &retryTransport{
+		transport:   transport,
+		maxAttempts: c.RetryMaxAttempts,
+		timeout:     requestTimeout,
+		waitMin:     c.RetryWaitMin,
+		waitMax:     c.RetryWaitMax,
+		errorCodes:  c.RetryErrorCodes,
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// tlsConfig returns the TLS configuration for the configured CA bundle,
+// client certificate and verification mode.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.Insecure}
+
+	if c.CAFile != "" {
+		pem, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading CA file %s: %s", c.CAFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("Error reading CA file %s: no valid PEM certificates found", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.ClientCertFile != "" || c.ClientKeyFile != "" {
+		if c.ClientCertFile == "" || c.ClientKeyFile == "" {
+			return nil, fmt.Errorf("Both 'client_cert_file' and 'client_key_file' should have values")
+		}
+
+		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading client certificate %s: %s", c.ClientCertFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
 }
@@ -0,0 +1,243 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testWriteFile writes the given PEM block to a file in dir and returns
+// the path of the file.
+func testWriteFile(t *testing.T, dir, name, typ string, b []byte) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// testClientCertificate generates a self-signed client certificate and
+// returns the paths of the certificate and key files.
+func testClientCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return testWriteFile(t, dir, "client.crt", "CERTIFICATE", cert),
+		testWriteFile(t, dir, "client.key", "EC PRIVATE KEY", der)
+}
+
+func TestConfigHTTPClient_tls(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudstack-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	caFile := testWriteFile(t, dir, "ca.crt", "CERTIFICATE", srv.Certificate().Raw)
+	certFile, keyFile := testClientCertificate(t, dir)
+
+	cases := []struct {
+		Config Config
+		Status int
+		Err    string
+	}{
+		// The test server is not trusted by the system pool
+		{
+			Config: Config{},
+			Err:    "certificate",
+		},
+		{
+			Config: Config{Insecure: true},
+			Status: http.StatusUnauthorized,
+		},
+		{
+			Config: Config{CAFile: caFile},
+			Status: http.StatusUnauthorized,
+		},
+		{
+			Config: Config{CAFile: caFile, ClientCertFile: certFile, ClientKeyFile: keyFile},
+			Status: http.StatusOK,
+		},
+	}
+
+	for i, tc := range cases {
+		client, err := tc.Config.httpClient(nil)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+
+		resp, err := client.Get(srv.URL)
+		if tc.Err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.Err) {
+				t.Fatalf("%d: expected error containing %q, got: %v", i, tc.Err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != tc.Status {
+			t.Fatalf("%d: bad status: %d", i, resp.StatusCode)
+		}
+	}
+}
+
+func TestConfigHTTPClient_invalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudstack-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := testClientCertificate(t, dir)
+
+	invalid := filepath.Join(dir, "invalid.crt")
+	if err := ioutil.WriteFile(invalid, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		Config Config
+		Err    string
+	}{
+		{
+			Config: Config{CAFile: filepath.Join(dir, "unknown.crt")},
+			Err:    "Error reading CA file",
+		},
+		{
+			Config: Config{CAFile: invalid},
+			Err:    "no valid PEM certificates found",
+		},
+		{
+			Config: Config{ClientCertFile: certFile},
+			Err:    "should have values",
+		},
+		{
+			Config: Config{ClientCertFile: invalid, ClientKeyFile: keyFile},
+			Err:    "Error loading client certificate",
+		},
+	}
+
+	for i, tc := range cases {
+		_, err := tc.Config.httpClient(nil)
+		if err == nil || !strings.Contains(err.Error(), tc.Err) {
+			t.Fatalf("%d: expected error containing %q, got: %v", i, tc.Err, err)
+		}
+	}
+}
+
+func TestConfigHTTPClient_apiLogFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudstack-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"listzonesresponse":{}}`))
+	}))
+	defer srv.Close()
+
+	logFile := filepath.Join(dir, "api.log")
+	c := &Config{APILogFile: logFile}
+	cs, err := c.newMeta()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clients with another timeout write to the same file
+	for _, client := range []*providerMeta{cs, withTimeout(cs, time.Minute)} {
+		resp, err := client.httpClient.Get(srv.URL + "?command=listZones&apiKey=key")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	b, err := ioutil.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"command":"listZones"`) ||
+		!strings.Contains(lines[0], `"apiKey":"REDACTED"`) {
+		t.Fatalf("Bad API log file: %s", b)
+	}
+
+	if err := cs.close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cs.apiLog.Write([]byte("\n")); err == nil {
+		t.Fatal("Expected the API log file to be closed")
+	}
+
+	c = &Config{APILogFile: filepath.Join(dir, "unknown", "api.log")}
+	if _, err := c.newMeta(); err == nil || !strings.Contains(err.Error(), "Error opening API log file") {
+		t.Fatalf("Expected an error opening the API log file, got: %v", err)
+	}
+}
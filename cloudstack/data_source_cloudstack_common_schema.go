@@ -21,6 +21,7 @@ package cloudstack
 
 import (
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func dataSourceFiltersSchema() *schema.Schema {
@@ -38,6 +39,12 @@ func dataSourceFiltersSchema() *schema.Schema {
 					Type:     schema.TypeString,
 					Required: true,
 				},
+				"operator": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      filterRegex,
+					ValidateFunc: validation.StringInSlice(filterOperators, false),
+				},
 			},
 		},
 	}
@@ -0,0 +1,114 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceCloudstackDiskOffering() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudstackDiskOfferingRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			// Computed values
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"display_text": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"disk_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"is_customized": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"storage_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceCloudstackDiskOfferingRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	p := cs.DiskOffering.NewListDiskOfferingsParams()
+
+	csOfferings, err := cs.DiskOffering.ListDiskOfferings(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list disk offerings: %s", err)
+	}
+
+	filters, err := parseFilters(d.Get("filter").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
+	var offerings []*cloudstack.DiskOffering
+
+	for _, o := range csOfferings.DiskOfferings {
+		match, err := applyFilters(o, filters)
+		if err != nil {
+			return err
+		}
+
+		if match {
+			offerings = append(offerings, o)
+		}
+	}
+
+	if len(offerings) == 0 {
+		return fmt.Errorf("No disk offering is matching with the specified filters")
+	}
+
+	if len(offerings) > 1 {
+		return fmt.Errorf("More than one disk offering is matching with the specified filters")
+	}
+	log.Printf("[DEBUG] Selected disk offering: %s\n", offerings[0].Name)
+
+	return diskOfferingDescriptionAttributes(d, offerings[0])
+}
+
+func diskOfferingDescriptionAttributes(d *schema.ResourceData, offering *cloudstack.DiskOffering) error {
+	d.SetId(offering.Id)
+	d.Set("name", offering.Name)
+	d.Set("display_text", offering.Displaytext)
+	d.Set("disk_size", int(offering.Disksize))
+	d.Set("is_customized", offering.Iscustomized)
+	d.Set("storage_type", offering.Storagetype)
+
+	return nil
+}
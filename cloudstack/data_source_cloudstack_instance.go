@@ -0,0 +1,156 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceCloudstackInstance() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudstackInstanceRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			// Computed values
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"display_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"service_offering": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"template": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"ip_address": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"network_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func dataSourceCloudstackInstanceRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	p := cs.VirtualMachine.NewListVirtualMachinesParams()
+	p.SetListall(true)
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	csInstances, err := cs.VirtualMachine.ListVirtualMachines(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list instances: %s", err)
+	}
+
+	filters, err := parseFilters(d.Get("filter").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
+	var instances []*cloudstack.VirtualMachine
+
+	for _, vm := range csInstances.VirtualMachines {
+		match, err := applyFilters(vm, filters)
+		if err != nil {
+			return err
+		}
+
+		if match {
+			instances = append(instances, vm)
+		}
+	}
+
+	if len(instances) == 0 {
+		return fmt.Errorf("No instance is matching with the specified filters")
+	}
+
+	if len(instances) > 1 {
+		return fmt.Errorf("More than one instance is matching with the specified filters")
+	}
+	log.Printf("[DEBUG] Selected instance: %s\n", instances[0].Name)
+
+	return instanceDescriptionAttributes(d, instances[0])
+}
+
+func instanceDescriptionAttributes(d *schema.ResourceData, instance *cloudstack.VirtualMachine) error {
+	d.SetId(instance.Id)
+	d.Set("name", instance.Name)
+	d.Set("display_name", instance.Displayname)
+	d.Set("state", instance.State)
+	d.Set("service_offering", instance.Serviceofferingname)
+	d.Set("template", instance.Templatename)
+	d.Set("zone", instance.Zonename)
+	d.Set("project", instance.Project)
+
+	if len(instance.Nic) > 0 {
+		d.Set("ip_address", instance.Nic[0].Ipaddress)
+		d.Set("network_id", instance.Nic[0].Networkid)
+	}
+
+	tags := make(map[string]interface{})
+	for _, tag := range instance.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	d.Set("tags", tags)
+
+	return nil
+}
@@ -0,0 +1,141 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceCloudstackIPAddress() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudstackIPAddressRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			// Computed values
+			"ip_address": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"is_source_nat": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"is_static_nat": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"network_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"vpc_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func dataSourceCloudstackIPAddressRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	p := cs.Address.NewListPublicIpAddressesParams()
+	p.SetListall(true)
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	csIPAddresses, err := cs.Address.ListPublicIpAddresses(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list IP addresses: %s", err)
+	}
+
+	filters, err := parseFilters(d.Get("filter").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
+	var ipaddresses []*cloudstack.PublicIpAddress
+
+	for _, ip := range csIPAddresses.PublicIpAddresses {
+		match, err := applyFilters(ip, filters)
+		if err != nil {
+			return err
+		}
+
+		if match {
+			ipaddresses = append(ipaddresses, ip)
+		}
+	}
+
+	if len(ipaddresses) == 0 {
+		return fmt.Errorf("No IP address is matching with the specified filters")
+	}
+
+	if len(ipaddresses) > 1 {
+		return fmt.Errorf("More than one IP address is matching with the specified filters")
+	}
+	log.Printf("[DEBUG] Selected IP address: %s\n", ipaddresses[0].Ipaddress)
+
+	return ipAddressDescriptionAttributes(d, ipaddresses[0])
+}
+
+func ipAddressDescriptionAttributes(d *schema.ResourceData, ip *cloudstack.PublicIpAddress) error {
+	d.SetId(ip.Id)
+	d.Set("ip_address", ip.Ipaddress)
+	d.Set("is_source_nat", ip.Issourcenat)
+	d.Set("is_static_nat", ip.Isstaticnat)
+	d.Set("network_id", ip.Associatednetworkid)
+	d.Set("vpc_id", ip.Vpcid)
+	d.Set("zone", ip.Zonename)
+	d.Set("project", ip.Project)
+
+	tags := make(map[string]interface{})
+	for _, tag := range ip.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	d.Set("tags", tags)
+
+	return nil
+}
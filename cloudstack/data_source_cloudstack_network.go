@@ -0,0 +1,165 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceCloudstackNetwork() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudstackNetworkRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			// Computed values
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"display_text": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"cidr": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"gateway": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"netmask": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"network_domain": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"network_offering": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"vpc_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"acl_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func dataSourceCloudstackNetworkRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	p := cs.Network.NewListNetworksParams()
+	p.SetListall(true)
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	csNetworks, err := cs.Network.ListNetworks(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list networks: %s", err)
+	}
+
+	filters, err := parseFilters(d.Get("filter").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
+	var networks []*cloudstack.Network
+
+	for _, n := range csNetworks.Networks {
+		match, err := applyFilters(n, filters)
+		if err != nil {
+			return err
+		}
+
+		if match {
+			networks = append(networks, n)
+		}
+	}
+
+	if len(networks) == 0 {
+		return fmt.Errorf("No network is matching with the specified filters")
+	}
+
+	if len(networks) > 1 {
+		return fmt.Errorf("More than one network is matching with the specified filters")
+	}
+	log.Printf("[DEBUG] Selected network: %s\n", networks[0].Name)
+
+	return networkDescriptionAttributes(d, networks[0])
+}
+
+func networkDescriptionAttributes(d *schema.ResourceData, network *cloudstack.Network) error {
+	d.SetId(network.Id)
+	d.Set("name", network.Name)
+	d.Set("display_text", network.Displaytext)
+	d.Set("cidr", network.Cidr)
+	d.Set("gateway", network.Gateway)
+	d.Set("netmask", network.Netmask)
+	d.Set("network_domain", network.Networkdomain)
+	d.Set("network_offering", network.Networkofferingname)
+	d.Set("vpc_id", network.Vpcid)
+	d.Set("acl_id", network.Aclid)
+	d.Set("zone", network.Zonename)
+	d.Set("project", network.Project)
+
+	tags := make(map[string]interface{})
+	for _, tag := range network.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	d.Set("tags", tags)
+
+	return nil
+}
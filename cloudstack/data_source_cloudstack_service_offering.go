@@ -0,0 +1,120 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceCloudstackServiceOffering() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudstackServiceOfferingRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			// Computed values
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"display_text": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"cpu_number": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"cpu_speed": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"memory": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"storage_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceCloudstackServiceOfferingRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	p := cs.ServiceOffering.NewListServiceOfferingsParams()
+
+	csOfferings, err := cs.ServiceOffering.ListServiceOfferings(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list service offerings: %s", err)
+	}
+
+	filters, err := parseFilters(d.Get("filter").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
+	var offerings []*cloudstack.ServiceOffering
+
+	for _, o := range csOfferings.ServiceOfferings {
+		match, err := applyFilters(o, filters)
+		if err != nil {
+			return err
+		}
+
+		if match {
+			offerings = append(offerings, o)
+		}
+	}
+
+	if len(offerings) == 0 {
+		return fmt.Errorf("No service offering is matching with the specified filters")
+	}
+
+	if len(offerings) > 1 {
+		return fmt.Errorf("More than one service offering is matching with the specified filters")
+	}
+	log.Printf("[DEBUG] Selected service offering: %s\n", offerings[0].Name)
+
+	return serviceOfferingDescriptionAttributes(d, offerings[0])
+}
+
+func serviceOfferingDescriptionAttributes(d *schema.ResourceData, offering *cloudstack.ServiceOffering) error {
+	d.SetId(offering.Id)
+	d.Set("name", offering.Name)
+	d.Set("display_text", offering.Displaytext)
+	d.Set("cpu_number", offering.Cpunumber)
+	d.Set("cpu_speed", offering.Cpuspeed)
+	d.Set("memory", offering.Memory)
+	d.Set("storage_type", offering.Storagetype)
+
+	return nil
+}
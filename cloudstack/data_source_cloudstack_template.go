@@ -20,16 +20,27 @@
 package cloudstack
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
+// Template selection strategies
+const (
+	templateSelectionMostRecent     = "most_recent"
+	templateSelectionOldest         = "oldest"
+	templateSelectionSingle         = "single"
+	templateSelectionHighestVersion = "highest_version"
+)
+
 func dataSourceCloudstackTemplate() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceCloudstackTemplateRead,
@@ -41,6 +52,34 @@ func dataSourceCloudstackTemplate() *schema.Resource {
 				Required: true,
 			},
 
+			"selection": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  templateSelectionMostRecent,
+				ValidateFunc: validation.StringInSlice([]string{
+					templateSelectionMostRecent,
+					templateSelectionOldest,
+					templateSelectionSingle,
+					templateSelectionHighestVersion,
+				}, false),
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"hypervisor": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
 			// Computed values
 			"template_id": {
 				Type:     schema.TypeString,
@@ -72,38 +111,92 @@ func dataSourceCloudstackTemplate() *schema.Resource {
 				Computed: true,
 			},
 
-			"hypervisor": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-
 			"size": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
 
 			"tags": tagsSchema(),
+
+			"templates": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"display_text": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"created": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"hypervisor": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"zone_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
 func dataSourceCloudstackTemplateRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	p := cloudstack.ListTemplatesParams{}
 	p.SetListall(true)
 	p.SetTemplatefilter(d.Get("template_filter").(string))
 
+	if zone, ok := d.GetOk("zone"); ok {
+		zoneid, e := retrieveID(cs, "zone", zone.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetZoneid(zoneid)
+	}
+
+	if hypervisor, ok := d.GetOk("hypervisor"); ok {
+		p.SetHypervisor(hypervisor.(string))
+	}
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(&p, cs, d); err != nil {
+		return err
+	}
+
 	csTemplates, err := cs.Template.ListTemplates(&p)
 	if err != nil {
 		return fmt.Errorf("Failed to list templates: %s", err)
 	}
 
-	filters := d.Get("filter")
+	filters, err := parseFilters(d.Get("filter").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
 	var templates []*cloudstack.Template
 
 	for _, t := range csTemplates.Templates {
-		match, err := applyFilters(t, filters.(*schema.Set))
+		match, err := applyFilters(t, filters)
 		if err != nil {
 			return err
 		}
@@ -114,15 +207,30 @@ func dataSourceCloudstackTemplateRead(d *schema.ResourceData, meta interface{})
 	}
 
 	if len(templates) == 0 {
-		return fmt.Errorf("No template is matching with the specified regex")
+		return fmt.Errorf("No template is matching with the specified filters")
 	}
 
-	template, err := latestTemplate(templates)
+	templates, err = sortTemplates(templates, d.Get("selection").(string))
 	if err != nil {
 		return err
 	}
+
+	template := templates[0]
 	log.Printf("[DEBUG] Selected template: %s\n", template.Displaytext)
 
+	var list []map[string]interface{}
+	for _, t := range templates {
+		list = append(list, map[string]interface{}{
+			"id":           t.Id,
+			"name":         t.Name,
+			"display_text": t.Displaytext,
+			"created":      t.Created,
+			"hypervisor":   t.Hypervisor,
+			"zone_id":      t.Zoneid,
+		})
+	}
+	d.Set("templates", list)
+
 	return templateDescriptionAttributes(d, template)
 }
 
@@ -146,44 +254,138 @@ func templateDescriptionAttributes(d *schema.ResourceData, template *cloudstack.
 	return nil
 }
 
-func latestTemplate(templates []*cloudstack.Template) (*cloudstack.Template, error) {
-	var latest time.Time
-	var template *cloudstack.Template
+// sortTemplates orders the matching templates using the given selection
+// strategy, so the first template is the one to select.
+func sortTemplates(templates []*cloudstack.Template, selection string) ([]*cloudstack.Template, error) {
+	switch selection {
+	case templateSelectionMostRecent, templateSelectionOldest:
+		created := make(map[string]time.Time, len(templates))
+		for _, t := range templates {
+			c, err := time.Parse("2006-01-02T15:04:05-0700", t.Created)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse creation date of a template: %s", err)
+			}
+			created[t.Id] = c
+		}
 
-	for _, t := range templates {
-		created, err := time.Parse("2006-01-02T15:04:05-0700", t.Created)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to parse creation date of a template: %s", err)
+		sort.SliceStable(templates, func(i, j int) bool {
+			if selection == templateSelectionOldest {
+				return created[templates[i].Id].Before(created[templates[j].Id])
+			}
+			return created[templates[i].Id].After(created[templates[j].Id])
+		})
+
+	case templateSelectionSingle:
+		// A template that is available in multiple zones is listed once
+		// per zone, so only count the distinct templates
+		seen := make(map[string]bool)
+		var names []string
+		for _, t := range templates {
+			if !seen[t.Id] {
+				seen[t.Id] = true
+				names = append(names, fmt.Sprintf("%s (%s)", t.Name, t.Id))
+			}
+		}
+		if len(names) > 1 {
+			return nil, fmt.Errorf(
+				"Found %d templates matching the specified filters, expected only one: %s",
+				len(names), strings.Join(names, ", "))
+		}
+
+	case templateSelectionHighestVersion:
+		var versioned []*cloudstack.Template
+		versions := make(map[string]*templateVersion)
+
+		for _, t := range templates {
+			v := parseTemplateVersion(t.Name)
+			if v == nil {
+				log.Printf("[DEBUG] Ignoring template %s: no version found in its name", t.Name)
+				continue
+			}
+			versions[t.Id] = v
+			versioned = append(versioned, t)
+		}
+
+		if len(versioned) == 0 {
+			return nil, fmt.Errorf("None of the matching templates has a version in its name")
 		}
 
-		if created.After(latest) {
-			latest = created
-			template = t
+		sort.SliceStable(versioned, func(i, j int) bool {
+			return versions[versioned[j].Id].less(versions[versioned[i].Id])
+		})
+
+		// Skip the other zones of the selected template, as those are
+		// listed as well
+		for _, t := range versioned[1:] {
+			if t.Id == versioned[0].Id {
+				continue
+			}
+			if !versions[t.Id].less(versions[versioned[0].Id]) {
+				return nil, fmt.Errorf(
+					"Found multiple templates with the highest version %s: %s (%s) and %s (%s)",
+					versions[versioned[0].Id], versioned[0].Name, versioned[0].Id, t.Name, t.Id)
+			}
+			break
 		}
+
+		templates = versioned
+
+	default:
+		return nil, fmt.Errorf("Unknown template selection: %s", selection)
 	}
 
-	return template, nil
+	return templates, nil
 }
 
-func applyFilters(template *cloudstack.Template, filters *schema.Set) (bool, error) {
-	var templateJSON map[string]interface{}
-	t, _ := json.Marshal(template)
-	json.Unmarshal(t, &templateJSON)
+// templateVersion is a semantic version parsed from the name of a template.
+type templateVersion struct {
+	major, minor, patch int
+	prerelease          string
+}
 
-	for _, f := range filters.List() {
-		m := f.(map[string]interface{})
+var templateVersionRegexp = regexp.MustCompile(
+	`(\d+)\.(\d+)(?:\.(\d+))?(?:-([0-9A-Za-z]+(?:\.[0-9A-Za-z]+)*))?`)
 
-		r, err := regexp.Compile(m["value"].(string))
-		if err != nil {
-			return false, fmt.Errorf("Invalid regex: %s", err)
-		}
+// parseTemplateVersion returns the first semantic version found in the given
+// name, or nil if the name does not contain a version.
+func parseTemplateVersion(name string) *templateVersion {
+	m := templateVersionRegexp.FindStringSubmatch(name)
+	if m == nil {
+		return nil
+	}
 
-		templateField := templateJSON[m["name"].(string)].(string)
-		if !r.MatchString(templateField) {
-			return false, nil
-		}
+	v := &templateVersion{prerelease: m[4]}
+	v.major, _ = strconv.Atoi(m[1])
+	v.minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.patch, _ = strconv.Atoi(m[3])
+	}
 
+	return v
+}
+
+// less returns true if v is a lower version than o. A pre-release is lower
+// than the release of the same version.
+func (v *templateVersion) less(o *templateVersion) bool {
+	if v.major != o.major {
+		return v.major < o.major
+	}
+	if v.minor != o.minor {
+		return v.minor < o.minor
+	}
+	if v.patch != o.patch {
+		return v.patch < o.patch
 	}
+	if v.prerelease == "" || o.prerelease == "" {
+		return v.prerelease != "" && o.prerelease == ""
+	}
+	return v.prerelease < o.prerelease
+}
 
-	return true, nil
+func (v *templateVersion) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if v.prerelease != "" {
+		s += "-" + v.prerelease
+	}
+	return s
 }
@@ -0,0 +1,163 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccCloudStackTemplateDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackTemplateDataSource_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.cloudstack_template.foo", "name", "CentOS 5.6 (64-bit) no GUI (Simulator)"),
+					resource.TestCheckResourceAttr(
+						"data.cloudstack_template.foo", "hypervisor", "Simulator"),
+					resource.TestCheckResourceAttr(
+						"data.cloudstack_template.foo", "templates.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"data.cloudstack_template.foo", "id", "data.cloudstack_template.foo", "templates.0.id"),
+				),
+			},
+		},
+	})
+}
+
+func TestSortTemplates(t *testing.T) {
+	templates := func() []*cloudstack.Template {
+		return []*cloudstack.Template{
+			{Id: "1", Name: "ubuntu-1.9.0", Created: "2020-03-01T00:00:00+0000"},
+			{Id: "2", Name: "ubuntu-1.10.0-rc1", Created: "2020-04-01T00:00:00+0000"},
+			{Id: "3", Name: "ubuntu-1.10.0", Created: "2020-02-01T00:00:00+0000"},
+			{Id: "4", Name: "ubuntu-latest", Created: "2020-05-01T00:00:00+0000"},
+		}
+	}
+
+	cases := []struct {
+		Selection string
+		Templates []*cloudstack.Template
+		Order     []string
+		Err       string
+	}{
+		{
+			Selection: templateSelectionMostRecent,
+			Templates: templates(),
+			Order:     []string{"4", "2", "1", "3"},
+		},
+		{
+			Selection: templateSelectionOldest,
+			Templates: templates(),
+			Order:     []string{"3", "1", "2", "4"},
+		},
+		{
+			Selection: templateSelectionHighestVersion,
+			Templates: templates(),
+			Order:     []string{"3", "2", "1"},
+		},
+		{
+			Selection: templateSelectionSingle,
+			Templates: templates()[:1],
+			Order:     []string{"1"},
+		},
+		{
+			Selection: templateSelectionSingle,
+			Templates: templates(),
+			Err:       "expected only one",
+		},
+		{
+			Selection: templateSelectionHighestVersion,
+			Templates: templates()[3:],
+			Err:       "has a version",
+		},
+		{
+			Selection: templateSelectionHighestVersion,
+			Templates: append(templates(), &cloudstack.Template{Id: "5", Name: "ubuntu-1.10"}),
+			Err:       "multiple templates with the highest version 1.10.0",
+		},
+		{
+			// A template in two zones is listed twice
+			Selection: templateSelectionSingle,
+			Templates: []*cloudstack.Template{
+				{Id: "1", Name: "ubuntu-1.9.0", Zoneid: "zone-1"},
+				{Id: "1", Name: "ubuntu-1.9.0", Zoneid: "zone-2"},
+			},
+			Order: []string{"1", "1"},
+		},
+		{
+			Selection: templateSelectionSingle,
+			Templates: append(templates()[:1], templates()[:2]...),
+			Err:       "Found 2 templates",
+		},
+		{
+			Selection: templateSelectionHighestVersion,
+			Templates: append(templates(), &cloudstack.Template{Id: "3", Name: "ubuntu-1.10.0"}),
+			Order:     []string{"3", "3", "2", "1"},
+		},
+	}
+
+	for i, tc := range cases {
+		sorted, err := sortTemplates(tc.Templates, tc.Selection)
+		if tc.Err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.Err) {
+				t.Fatalf("%d: expected error containing %q, got: %v", i, tc.Err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+
+		var order []string
+		for _, t := range sorted {
+			order = append(order, t.Id)
+		}
+		if strings.Join(order, ",") != strings.Join(tc.Order, ",") {
+			t.Fatalf("%d: bad order: %v, expected: %v", i, order, tc.Order)
+		}
+	}
+}
+
+const testAccCloudStackTemplateDataSource_basic = `
+data "cloudstack_template" "foo" {
+  template_filter = "featured"
+  selection = "single"
+  zone = "Sandbox-simulator"
+  hypervisor = "Simulator"
+
+  filter {
+    name = "name"
+    value = "^CentOS 5\\.6"
+  }
+
+  filter {
+    name = "isready"
+    value = "true"
+    operator = "exact"
+  }
+}`
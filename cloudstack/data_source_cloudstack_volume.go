@@ -0,0 +1,147 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceCloudstackVolume() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudstackVolumeRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			// Computed values
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"disk_offering": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"virtual_machine_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"device_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func dataSourceCloudstackVolumeRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	p := cs.Volume.NewListVolumesParams()
+	p.SetListall(true)
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	csVolumes, err := cs.Volume.ListVolumes(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list volumes: %s", err)
+	}
+
+	filters, err := parseFilters(d.Get("filter").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
+	var volumes []*cloudstack.Volume
+
+	for _, v := range csVolumes.Volumes {
+		match, err := applyFilters(v, filters)
+		if err != nil {
+			return err
+		}
+
+		if match {
+			volumes = append(volumes, v)
+		}
+	}
+
+	if len(volumes) == 0 {
+		return fmt.Errorf("No volume is matching with the specified filters")
+	}
+
+	if len(volumes) > 1 {
+		return fmt.Errorf("More than one volume is matching with the specified filters")
+	}
+	log.Printf("[DEBUG] Selected volume: %s\n", volumes[0].Name)
+
+	return volumeDescriptionAttributes(d, volumes[0])
+}
+
+func volumeDescriptionAttributes(d *schema.ResourceData, volume *cloudstack.Volume) error {
+	d.SetId(volume.Id)
+	d.Set("name", volume.Name)
+	d.Set("type", volume.Type)
+	d.Set("size", int(volume.Size/(1024*1024*1024)))
+	d.Set("disk_offering", volume.Diskofferingname)
+	d.Set("virtual_machine_id", volume.Virtualmachineid)
+	d.Set("device_id", int(volume.Deviceid))
+	d.Set("zone", volume.Zonename)
+	d.Set("project", volume.Project)
+
+	tags := make(map[string]interface{})
+	for _, tag := range volume.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	d.Set("tags", tags)
+
+	return nil
+}
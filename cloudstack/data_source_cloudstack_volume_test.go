@@ -0,0 +1,62 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccCloudStackVolumeDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVolumeDataSource_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.cloudstack_volume.foo", "id", "cloudstack_disk.foo", "id"),
+					resource.TestCheckResourceAttr(
+						"data.cloudstack_volume.foo", "type", "DATADISK"),
+					resource.TestCheckResourceAttr(
+						"data.cloudstack_volume.foo", "disk_offering", "Small"),
+					resource.TestCheckResourceAttr(
+						"data.cloudstack_volume.foo", "size", "5"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCloudStackVolumeDataSource_basic = `
+resource "cloudstack_disk" "foo" {
+  name = "terraform-disk"
+  disk_offering = "Small"
+  zone = "Sandbox-simulator"
+}
+
+data "cloudstack_volume" "foo" {
+  filter {
+    name = "name"
+    value = "^${cloudstack_disk.foo.name}$"
+  }
+}`
@@ -0,0 +1,141 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceCloudstackVPC() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudstackVPCRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			// Computed values
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"display_text": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"cidr": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"vpc_offering": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"network_domain": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"zone": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func dataSourceCloudstackVPCRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	p := cs.VPC.NewListVPCsParams()
+	p.SetListall(true)
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
+	csVPCs, err := cs.VPC.ListVPCs(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list VPCs: %s", err)
+	}
+
+	filters, err := parseFilters(d.Get("filter").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
+	var vpcs []*cloudstack.VPC
+
+	for _, v := range csVPCs.VPCs {
+		match, err := applyFilters(v, filters)
+		if err != nil {
+			return err
+		}
+
+		if match {
+			vpcs = append(vpcs, v)
+		}
+	}
+
+	if len(vpcs) == 0 {
+		return fmt.Errorf("No VPC is matching with the specified filters")
+	}
+
+	if len(vpcs) > 1 {
+		return fmt.Errorf("More than one VPC is matching with the specified filters")
+	}
+	log.Printf("[DEBUG] Selected VPC: %s\n", vpcs[0].Name)
+
+	return vpcDescriptionAttributes(d, vpcs[0])
+}
+
+func vpcDescriptionAttributes(d *schema.ResourceData, vpc *cloudstack.VPC) error {
+	d.SetId(vpc.Id)
+	d.Set("name", vpc.Name)
+	d.Set("display_text", vpc.Displaytext)
+	d.Set("cidr", vpc.Cidr)
+	d.Set("vpc_offering", vpc.Vpcofferingname)
+	d.Set("network_domain", vpc.Networkdomain)
+	d.Set("zone", vpc.Zonename)
+	d.Set("project", vpc.Project)
+
+	tags := make(map[string]interface{})
+	for _, tag := range vpc.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	d.Set("tags", tags)
+
+	return nil
+}
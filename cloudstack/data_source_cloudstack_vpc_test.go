@@ -0,0 +1,64 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccCloudStackVPCDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVPCDataSource_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.cloudstack_vpc.foo", "id", "cloudstack_vpc.foo", "id"),
+					resource.TestCheckResourceAttr(
+						"data.cloudstack_vpc.foo", "cidr", "10.0.0.0/8"),
+					resource.TestCheckResourceAttr(
+						"data.cloudstack_vpc.foo", "tags.terraform-tag", "true"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCloudStackVPCDataSource_basic = `
+resource "cloudstack_vpc" "foo" {
+  name = "terraform-vpc"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  zone = "Sandbox-simulator"
+  tags = {
+    terraform-tag = "true"
+  }
+}
+
+data "cloudstack_vpc" "foo" {
+  filter {
+    name = "name"
+    value = "^${cloudstack_vpc.foo.name}$"
+  }
+}`
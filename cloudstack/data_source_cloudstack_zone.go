@@ -0,0 +1,134 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceCloudstackZone() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCloudstackZoneRead,
+		Schema: map[string]*schema.Schema{
+			"filter": dataSourceFiltersSchema(),
+
+			// Computed values
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"network_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"allocation_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"dhcp_provider": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"local_storage_enabled": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"security_groups_enabled": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func dataSourceCloudstackZoneRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	p := cs.Zone.NewListZonesParams()
+
+	csZones, err := cs.Zone.ListZones(p)
+	if err != nil {
+		return fmt.Errorf("Failed to list zones: %s", err)
+	}
+
+	filters, err := parseFilters(d.Get("filter").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
+	var zones []*cloudstack.Zone
+
+	for _, z := range csZones.Zones {
+		match, err := applyFilters(z, filters)
+		if err != nil {
+			return err
+		}
+
+		if match {
+			zones = append(zones, z)
+		}
+	}
+
+	if len(zones) == 0 {
+		return fmt.Errorf("No zone is matching with the specified filters")
+	}
+
+	if len(zones) > 1 {
+		return fmt.Errorf("More than one zone is matching with the specified filters")
+	}
+	log.Printf("[DEBUG] Selected zone: %s\n", zones[0].Name)
+
+	return zoneDescriptionAttributes(d, zones[0])
+}
+
+func zoneDescriptionAttributes(d *schema.ResourceData, zone *cloudstack.Zone) error {
+	d.SetId(zone.Id)
+	d.Set("name", zone.Name)
+	d.Set("description", zone.Description)
+	d.Set("network_type", zone.Networktype)
+	d.Set("allocation_state", zone.Allocationstate)
+	d.Set("dhcp_provider", zone.Dhcpprovider)
+	d.Set("local_storage_enabled", zone.Localstorageenabled)
+	d.Set("security_groups_enabled", zone.Securitygroupsenabled)
+
+	tags := make(map[string]interface{})
+	for _, tag := range zone.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	d.Set("tags", tags)
+
+	return nil
+}
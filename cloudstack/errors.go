@@ -0,0 +1,207 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+// CloudStack API error codes, which are also used as the HTTP status of
+// the response. See org.apache.cloudstack.api.ApiErrorCode.
+const (
+	errorCodeUnauthorized     = 401
+	errorCodeAPILimitExceeded = 429
+	errorCodeParamError       = 431
+	errorCodeInternalError    = 530
+	errorCodeNetworkConflict  = 537
+)
+
+// CloudStack exception error codes, that identify the exception that caused
+// an API error. See com.cloud.utils.exception.CSExceptionErrorCode.
+const (
+	csErrorCodeAuthentication      = 4290
+	csErrorCodeConcurrentOperation = 4300
+	csErrorCodeNetworkRuleConflict = 4360
+	csErrorCodePermissionDenied    = 4365
+	csErrorCodeInvalidParameter    = 4350
+	csErrorCodeRequestLimit        = 4545
+	csErrorCodeServerAPI           = 9999
+)
+
+// The classes of errors returned by the CloudStack API, which can be tested
+// for using errors.Is on the result of classifyError.
+var (
+	errNotFound     = errors.New("entity does not exist")
+	errConflict     = errors.New("conflicting concurrent operation")
+	errThrottled    = errors.New("API request limit exceeded")
+	errUnauthorized = errors.New("unauthorized")
+)
+
+var (
+	// apiErrorRe matches the errors returned by the CloudStack client for
+	// failed API calls
+	apiErrorRe = regexp.MustCompile(`CloudStack API error (\d+) \(CSExceptionErrorCode: (\d+)\): (.*)`)
+
+	// jobErrorRe matches the errors returned by the CloudStack client for
+	// failed async jobs, which contain the job result as JSON
+	jobErrorRe = regexp.MustCompile(`Undefined error: (\{.*\})`)
+)
+
+// apiError is an error returned by the CloudStack API, either directly or as
+// the result of a failed async job.
+type apiError struct {
+	ErrorCode   int    `json:"errorcode"`
+	CSErrorCode int    `json:"cserrorcode"`
+	ErrorText   string `json:"errortext"`
+
+	err   error
+	class error
+}
+
+// Error implements the error interface and returns the original error.
+func (e *apiError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the original error.
+func (e *apiError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the error belongs to the given class of errors.
+func (e *apiError) Is(target error) bool {
+	return e.class != nil && e.class == target
+}
+
+// classifyError parses the error codes of an error returned by the
+// CloudStack client. If the error is an API error, an *apiError is returned
+// with its class set, otherwise the error is returned as is.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *apiError
+	if errors.As(err, &e) {
+		return e
+	}
+
+	e = &apiError{err: err}
+
+	if m := apiErrorRe.FindStringSubmatch(err.Error()); m != nil {
+		e.ErrorCode, _ = strconv.Atoi(m[1])
+		e.CSErrorCode, _ = strconv.Atoi(m[2])
+		e.ErrorText = m[3]
+	} else if m := jobErrorRe.FindStringSubmatch(err.Error()); m != nil {
+		if json.Unmarshal([]byte(m[1]), e) != nil {
+			return err
+		}
+	} else {
+		return err
+	}
+
+	switch {
+	case e.ErrorCode == errorCodeUnauthorized,
+		e.CSErrorCode == csErrorCodeAuthentication,
+		e.CSErrorCode == csErrorCodePermissionDenied:
+		e.class = errUnauthorized
+	case e.ErrorCode == errorCodeAPILimitExceeded,
+		e.CSErrorCode == csErrorCodeRequestLimit:
+		e.class = errThrottled
+	// A resource that is still in use is not a conflict, as it stays in
+	// use until its dependencies are removed
+	case e.ErrorCode == errorCodeNetworkConflict,
+		e.CSErrorCode == csErrorCodeConcurrentOperation,
+		e.CSErrorCode == csErrorCodeNetworkRuleConflict:
+		e.class = errConflict
+	case e.ErrorCode == errorCodeParamError &&
+		(e.CSErrorCode == csErrorCodeInvalidParameter || e.CSErrorCode == csErrorCodeServerAPI):
+		// CloudStack rejects an ID that does not exist as an invalid
+		// parameter value, which is only a missing entity for the calls
+		// that look up or delete an entity by its own ID
+		e.class = errNotFound
+	}
+
+	return e
+}
+
+// isAPIError returns true if the error was returned by the CloudStack API,
+// which means the API call was rejected or its async job failed.
+func isAPIError(err error) bool {
+	var e *apiError
+	return errors.As(classifyError(err), &e)
+}
+
+// isNotFound returns true if the error is caused by an entity that does
+// not exist (anymore). It must only be used for the errors of API calls that
+// look up or delete an entity by its own ID, as any invalid parameter value
+// is classified as not found.
+func isNotFound(err error) bool {
+	return errors.Is(classifyError(err), errNotFound)
+}
+
+// isConflict returns true if the error is caused by a concurrent operation
+// on the same entity or network rules.
+func isConflict(err error) bool {
+	return errors.Is(classifyError(err), errConflict)
+}
+
+// isThrottled returns true if the error is caused by the API request limit.
+func isThrottled(err error) bool {
+	return errors.Is(classifyError(err), errThrottled)
+}
+
+// isUnauthorized returns true if the error is caused by invalid credentials
+// or missing permissions.
+func isUnauthorized(err error) bool {
+	return errors.Is(classifyError(err), errUnauthorized)
+}
+
+// retryTransient calls f until it succeeds, fails with an error other than
+// a conflict or throttling error, or the timeout expires. An entity that is
+// still in use fails at once, as waiting doesn't remove its dependencies.
+// A zero timeout, the default of the resource timeouts, means the Terraform
+// default of 20 minutes.
+func retryTransient(timeout time.Duration, f func() error) error {
+	if timeout <= 0 {
+		timeout = 20 * time.Minute
+	}
+
+	return resource.Retry(timeout, func() *resource.RetryError {
+		err := f()
+		if err == nil {
+			return nil
+		}
+
+		if isConflict(err) || isThrottled(err) {
+			log.Printf("[DEBUG] Retrying after transient error: %s", err)
+			return resource.RetryableError(err)
+		}
+
+		return resource.NonRetryableError(err)
+	})
+}
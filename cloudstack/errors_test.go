@@ -0,0 +1,174 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+)
+
+func TestClassifyError(t *testing.T) {
+	apiErr := func(code int, cscode int, text string) error {
+		return (&cloudstack.CSError{ErrorCode: code, CSErrorCode: cscode, ErrorText: text}).Error()
+	}
+
+	cases := []struct {
+		Err   error
+		Class error
+	}{
+		{apiErr(431, 9999, "Unable to execute API command deletenetwork due to invalid value. "+
+			"Invalid parameter id value=42 due to incorrect long value format, or entity does not exist "+
+			"or due to incorrect parameter annotation for the field in api cmd class."), errNotFound},
+		{apiErr(431, 4350, "Unable to execute API command listnetworks due to invalid value. "+
+			"Invalid parameter id value=42 due to incorrect long value format, or entity does not exist "+
+			"or due to incorrect parameter annotation for the field in api cmd class."), errNotFound},
+		// Only the error codes are used, so reworded or localized messages
+		// are classified as well
+		{apiErr(431, 4350, "Unable to find volume by id 42"), errNotFound},
+		{apiErr(431, 9999, "Valeur du paramètre id=42 non valide"), errNotFound},
+		{apiErr(431, 4250, "Invalid parameter id value=42 due to incorrect long value format, "+
+			"or entity does not exist"), nil},
+		{apiErr(530, 4250, "Invalid parameter id value=42 due to incorrect long value format, "+
+			"or entity does not exist"), nil},
+		{apiErr(530, 4250, "Unable to find network with id 42"), nil},
+		{apiErr(530, 4250, "Internal error executing command"), nil},
+		{apiErr(536, 4375, "Network is in use"), nil},
+		{apiErr(530, 4300, "Failed to acquire lock"), errConflict},
+		{apiErr(537, 4360, "The range specified conflicts with rule 42"), errConflict},
+		{apiErr(429, 4545, "The given user has reached his/her account api limit"), errThrottled},
+		{apiErr(401, 0, "unable to verify user credentials and/or request signature"), errUnauthorized},
+		{apiErr(531, 4365, "Account does not have permission to operate within domain"), errUnauthorized},
+		{fmt.Errorf("Undefined error: %s",
+			`{"cserrorcode":4300,"errorcode":530,"errortext":"Failed to acquire lock"}`), errConflict},
+		{fmt.Errorf("Undefined error: %s",
+			`{"cserrorcode":4350,"errorcode":431,"errortext":"Invalid parameter id value=42 due to `+
+				`incorrect long value format, or entity does not exist"}`), errNotFound},
+		{fmt.Errorf("Undefined error: %s",
+			`{"cserrorcode":4350,"errorcode":431,"errortext":"Unable to find VM by id"}`), errNotFound},
+		{fmt.Errorf("No match found for 42: &{Count:0 Networks:[]}"), nil},
+		{fmt.Errorf("Error deleting network: %s", apiErr(530, 4300, "Failed to acquire lock")), errConflict},
+		{errors.New("connection refused"), nil},
+	}
+
+	for i, tc := range cases {
+		err := classifyError(tc.Err)
+		if err.Error() != tc.Err.Error() {
+			t.Fatalf("%d: expected the original error, got: %s", i, err)
+		}
+
+		for _, class := range []error{errNotFound, errConflict, errThrottled, errUnauthorized} {
+			if errors.Is(err, class) != (class == tc.Class) {
+				t.Fatalf("%d: bad class of error %q, expected: %v", i, tc.Err, tc.Class)
+			}
+		}
+	}
+
+	if classifyError(nil) != nil {
+		t.Fatal("Expected no error for a nil error")
+	}
+
+	if !isAPIError(apiErr(431, 4350, "Only scaling up the vm is supported")) ||
+		isAPIError(errors.New("connection refused")) || isAPIError(nil) {
+		t.Fatal("Expected only errors returned by the API to be API errors")
+	}
+
+	e := classifyError(apiErr(536, 4375, "Network is in use")).(*apiError)
+	if e.ErrorCode != 536 || e.CSErrorCode != 4375 || e.ErrorText != "Network is in use" {
+		t.Fatalf("Bad error codes: %#v", e)
+	}
+}
+
+func TestClassifyError_simulator(t *testing.T) {
+	s, cs := newSimulatorClient(t)
+	defer s.Close()
+
+	id := s.newID()
+
+	// A missing entity is reported by the count of the lookup
+	if _, count, err := cs.Network.GetNetworkByID(id); err == nil || count != 0 {
+		t.Fatalf("Expected no match, got %d and: %v", count, err)
+	}
+
+	if _, err := cs.Network.DeleteNetwork(cs.Network.NewDeleteNetworkParams(id)); !isNotFound(err) {
+		t.Fatalf("Expected a not found error, got: %v", err)
+	}
+
+	// An option that can't be resolved doesn't mean the entity is gone
+	_, count, err := cs.Network.GetNetworkByID(id, cloudstack.WithProject("unknown"))
+	if err == nil || count != -1 || isNotFound(err) {
+		t.Fatalf("Expected an error resolving the project, got %d and: %v", count, err)
+	}
+
+	c := cloudstack.NewAsyncClient(s.APIURL(), simulatorAPIKey, "wrong-secret", false)
+	if _, _, err := c.Zone.GetZoneID("Sandbox-simulator"); !isUnauthorized(err) {
+		t.Fatalf("Expected an unauthorized error, got: %v", err)
+	}
+}
+
+func TestRetryTransient(t *testing.T) {
+	conflict := (&cloudstack.CSError{ErrorCode: 530, CSErrorCode: 4300, ErrorText: "Failed to acquire lock"}).Error()
+	inUse := (&cloudstack.CSError{ErrorCode: 536, CSErrorCode: 4375, ErrorText: "Network is in use"}).Error()
+	notFound := fmt.Errorf("No match found for 42: &{Count:0}")
+
+	calls := 0
+	err := retryTransient(time.Minute, func() error {
+		if calls++; calls < 3 {
+			return conflict
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("Expected success after 3 calls, got %d calls and error: %v", calls, err)
+	}
+
+	calls = 0
+	err = retryTransient(time.Minute, func() error {
+		calls++
+		return notFound
+	})
+	if err != notFound || calls != 1 {
+		t.Fatalf("Expected the not found error after 1 call, got %d calls and error: %v", calls, err)
+	}
+
+	// An entity that is still in use is reported at once
+	calls = 0
+	err = retryTransient(time.Minute, func() error {
+		calls++
+		return inUse
+	})
+	if err != inUse || calls != 1 {
+		t.Fatalf("Expected the in use error after 1 call, got %d calls and error: %v", calls, err)
+	}
+
+	// A zero timeout must not expire before the first call returns
+	for i := 0; i < 100; i++ {
+		err = retryTransient(0, func() error {
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected success with a zero timeout, got: %v", err)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Supported filter operators
+const (
+	filterRegex = "regex"
+	filterExact = "exact"
+	filterEq    = "eq"
+	filterNe    = "ne"
+	filterLt    = "lt"
+	filterLte   = "lte"
+	filterGt    = "gt"
+	filterGte   = "gte"
+)
+
+var filterOperators = []string{
+	filterRegex, filterExact, filterEq, filterNe, filterLt, filterLte, filterGt, filterGte,
+}
+
+// filter is a single parsed filter block of a data source.
+type filter struct {
+	name     string
+	value    string
+	operator string
+	regex    *regexp.Regexp
+	number   float64
+}
+
+// parseFilters parses and validates the filter blocks of a data source.
+func parseFilters(filters *schema.Set) ([]*filter, error) {
+	var fs []*filter
+
+	for _, f := range filters.List() {
+		m := f.(map[string]interface{})
+
+		flt := &filter{
+			name:     m["name"].(string),
+			value:    m["value"].(string),
+			operator: filterRegex,
+		}
+		if op, ok := m["operator"].(string); ok && op != "" {
+			flt.operator = op
+		}
+
+		switch flt.operator {
+		case filterRegex:
+			r, err := regexp.Compile(flt.value)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid regex for filter %s: %s", flt.name, err)
+			}
+			flt.regex = r
+		case filterExact:
+		case filterEq, filterNe, filterLt, filterLte, filterGt, filterGte:
+			n, err := strconv.ParseFloat(flt.value, 64)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Invalid value for filter %s: operator %s requires a number, got: %s",
+					flt.name, flt.operator, flt.value)
+			}
+			flt.number = n
+		default:
+			return nil, fmt.Errorf(
+				"Invalid operator for filter %s: %s (expected one of: %s)",
+				flt.name, flt.operator, strings.Join(filterOperators, ", "))
+		}
+
+		fs = append(fs, flt)
+	}
+
+	return fs, nil
+}
+
+// applyFilters returns true if the given CloudStack resource matches all of
+// the given filters. The filter names refer to the fields of the API response,
+// where tags can be filtered using tags.<key> and other nested fields using
+// a dotted path.
+func applyFilters(resource interface{}, filters []*filter) (bool, error) {
+	var resourceJSON map[string]interface{}
+	r, err := json.Marshal(resource)
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(r, &resourceJSON); err != nil {
+		return false, err
+	}
+
+	for _, f := range filters {
+		values, err := filterValues(resourceJSON, f.name)
+		if err != nil {
+			return false, err
+		}
+
+		match := false
+		for _, v := range values {
+			ok, err := f.match(v)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				match = true
+				break
+			}
+		}
+
+		if !match {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// filterValues returns the values of the field with the given (dotted) name.
+// Lists yield one value per element, so a filter matches a list if any of its
+// elements match.
+func filterValues(resource map[string]interface{}, name string) ([]interface{}, error) {
+	parts := strings.Split(name, ".")
+
+	// Tags are returned as a list of key/value pairs
+	if parts[0] == "tags" && len(parts) == 2 {
+		if _, ok := resource["tags"]; !ok {
+			return nil, fmt.Errorf("Invalid filter name %s: resource has no tags", name)
+		}
+
+		tags, _ := resource["tags"].([]interface{})
+		for _, t := range tags {
+			if tag, ok := t.(map[string]interface{}); ok && tag["key"] == parts[1] {
+				return []interface{}{tag["value"]}, nil
+			}
+		}
+
+		// A missing tag never matches
+		return nil, nil
+	}
+
+	values := []interface{}{resource}
+	for i, part := range parts {
+		var next []interface{}
+
+		for _, v := range values {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf(
+					"Invalid filter name %s: %s is not an object", name, strings.Join(parts[:i], "."))
+			}
+
+			field, ok := m[part]
+			if !ok {
+				return nil, fmt.Errorf(
+					"Invalid filter name %s: unknown field %s", name, strings.Join(parts[:i+1], "."))
+			}
+
+			if l, ok := field.([]interface{}); ok {
+				next = append(next, l...)
+			} else {
+				next = append(next, field)
+			}
+		}
+
+		values = next
+	}
+
+	return values, nil
+}
+
+// match compares a single (JSON decoded) field value with the filter.
+func (f *filter) match(v interface{}) (bool, error) {
+	switch v.(type) {
+	case map[string]interface{}:
+		return false, fmt.Errorf(
+			"Invalid filter name %s: cannot filter on an object, filter on one of its fields instead", f.name)
+	}
+
+	switch f.operator {
+	case filterRegex:
+		return f.regex.MatchString(filterString(v)), nil
+	case filterExact:
+		return filterString(v) == f.value, nil
+	}
+
+	var n float64
+	switch v := v.(type) {
+	case float64:
+		n = v
+	case string:
+		var err error
+		if n, err = strconv.ParseFloat(v, 64); err != nil {
+			// Values that are not a number never match a numeric comparison
+			return false, nil
+		}
+	default:
+		return false, fmt.Errorf(
+			"Invalid filter %s: operator %s requires a numeric field", f.name, f.operator)
+	}
+
+	switch f.operator {
+	case filterEq:
+		return n == f.number, nil
+	case filterNe:
+		return n != f.number, nil
+	case filterLt:
+		return n < f.number, nil
+	case filterLte:
+		return n <= f.number, nil
+	case filterGt:
+		return n > f.number, nil
+	case filterGte:
+		return n >= f.number, nil
+	}
+
+	return false, nil
+}
+
+// filterString returns the string representation of a JSON decoded value.
+func filterString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,177 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func testFilters(t *testing.T, filters ...map[string]interface{}) *schema.Set {
+	raw := make([]interface{}, len(filters))
+	for i, f := range filters {
+		raw[i] = f
+	}
+
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"filter": dataSourceFiltersSchema(),
+	}, map[string]interface{}{"filter": raw})
+
+	return d.Get("filter").(*schema.Set)
+}
+
+func TestApplyFilters(t *testing.T) {
+	template := &cloudstack.Template{
+		Name:       "CentOS 7.1",
+		Hypervisor: "KVM",
+		Isready:    true,
+		Size:       10737418240,
+		Tags: []cloudstack.Tags{
+			{Key: "version", Value: "1.2.3"},
+		},
+	}
+
+	cases := []struct {
+		Filter map[string]interface{}
+		Match  bool
+		Err    string
+	}{
+		{
+			Filter: map[string]interface{}{"name": "name", "value": "^CentOS 7"},
+			Match:  true,
+		},
+		{
+			Filter: map[string]interface{}{"name": "name", "value": "CentOS", "operator": "exact"},
+			Match:  false,
+		},
+		{
+			Filter: map[string]interface{}{"name": "isready", "value": "true", "operator": "exact"},
+			Match:  true,
+		},
+		{
+			Filter: map[string]interface{}{"name": "size", "value": "10737418240"},
+			Match:  true,
+		},
+		{
+			Filter: map[string]interface{}{"name": "size", "value": "1073741824", "operator": "gt"},
+			Match:  true,
+		},
+		{
+			Filter: map[string]interface{}{"name": "size", "value": "1073741824", "operator": "lte"},
+			Match:  false,
+		},
+		{
+			Filter: map[string]interface{}{"name": "tags.version", "value": `^1\.2\.`},
+			Match:  true,
+		},
+		{
+			Filter: map[string]interface{}{"name": "tags.missing", "value": ".*"},
+			Match:  false,
+		},
+		{
+			Filter: map[string]interface{}{"name": "tags.key", "value": "version", "operator": "exact"},
+			Match:  false,
+		},
+		{
+			Filter: map[string]interface{}{"name": "unknown", "value": "foo"},
+			Err:    "unknown field unknown",
+		},
+		{
+			Filter: map[string]interface{}{"name": "name", "value": "foo", "operator": "gt"},
+			Err:    "requires a number",
+		},
+		{
+			Filter: map[string]interface{}{"name": "name", "value": "1", "operator": "gt"},
+			Match:  false,
+		},
+		{
+			Filter: map[string]interface{}{"name": "isready", "value": "1", "operator": "eq"},
+			Err:    "requires a numeric field",
+		},
+		{
+			Filter: map[string]interface{}{"name": "name", "value": "[", "operator": "regex"},
+			Err:    "Invalid regex",
+		},
+	}
+
+	for i, tc := range cases {
+		filters, err := parseFilters(testFilters(t, tc.Filter))
+		if err == nil {
+			var match bool
+			match, err = applyFilters(template, filters)
+			if err == nil && match != tc.Match {
+				t.Fatalf("%d: expected match to be %t, got %t", i, tc.Match, match)
+			}
+		}
+
+		if tc.Err == "" && err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+		if tc.Err != "" && (err == nil || !strings.Contains(err.Error(), tc.Err)) {
+			t.Fatalf("%d: expected error containing %q, got: %v", i, tc.Err, err)
+		}
+	}
+}
+
+func TestApplyFilters_multiple(t *testing.T) {
+	network := &cloudstack.Network{
+		Name: "terraform-network",
+		Cidr: "10.1.1.0/24",
+		Service: []cloudstack.NetworkServiceInternal{
+			{Name: "Dhcp"},
+			{Name: "SourceNat"},
+		},
+	}
+
+	filters, err := parseFilters(testFilters(t,
+		map[string]interface{}{"name": "name", "value": "terraform"},
+		map[string]interface{}{"name": "service.name", "value": "SourceNat", "operator": "exact"},
+	))
+	if err != nil {
+		t.Fatalf("Error parsing filters: %s", err)
+	}
+
+	match, err := applyFilters(network, filters)
+	if err != nil {
+		t.Fatalf("Error applying filters: %s", err)
+	}
+	if !match {
+		t.Fatal("Expected the network to match")
+	}
+
+	filters, err = parseFilters(testFilters(t,
+		map[string]interface{}{"name": "name", "value": "terraform"},
+		map[string]interface{}{"name": "cidr", "value": "10.2.", "operator": "regex"},
+	))
+	if err != nil {
+		t.Fatalf("Error parsing filters: %s", err)
+	}
+
+	match, err = applyFilters(network, filters)
+	if err != nil {
+		t.Fatalf("Error applying filters: %s", err)
+	}
+	if match {
+		t.Fatal("Expected the network not to match")
+	}
+}
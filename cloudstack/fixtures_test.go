@@ -0,0 +1,436 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+)
+
+// The acceptance tests can record the API calls they make into fixture files
+// and replay them later without a CloudStack API, by setting CLOUDSTACK_FIXTURES
+// to "record" or "replay". The fixtures are stored in CLOUDSTACK_FIXTURES_DIR,
+// which defaults to testdata/fixtures, in a file named after the test.
+const (
+	fixtureModeRecord = "record"
+	fixtureModeReplay = "replay"
+
+	defaultFixturesDir = "testdata/fixtures"
+)
+
+// fixtureVolatileParams are request parameters that hold credentials or
+// differ between runs. They are not recorded and ignored when replaying.
+var fixtureVolatileParams = map[string]bool{
+	"apikey":           true,
+	"expires":          true,
+	"sessionkey":       true,
+	"signature":        true,
+	"signatureversion": true,
+}
+
+// fixtureScrubbedFields are response fields that hold credentials, and are
+// replaced before the response is recorded.
+var fixtureScrubbedFields = map[string]bool{
+	"apikey":    true,
+	"secretkey": true,
+	"signature": true,
+}
+
+// fixtureInteraction is a recorded API call and its response.
+type fixtureInteraction struct {
+	Command string            `json:"command"`
+	Params  map[string]string `json:"params"`
+	Status  int               `json:"status"`
+	Body    string            `json:"body"`
+}
+
+// key returns the key used to match a request with a recorded call.
+func (i *fixtureInteraction) key() string {
+	params := url.Values{}
+	for k, v := range i.Params {
+		params.Set(k, v)
+	}
+	return i.Command + "?" + params.Encode()
+}
+
+// fixtures records or replays the API calls of the acceptance tests. Only
+// the calls made to the API of the acceptance tests are recorded, so tests
+// that start their own simulator are not affected.
+type fixtures struct {
+	mode   string
+	dir    string
+	apiURL string
+
+	mu sync.Mutex
+
+	// name is the name of the running test
+	name string
+
+	// recorded are the calls recorded for the running test
+	recorded []fixtureInteraction
+
+	// replay are the calls to replay for the running test, by key
+	replay map[string][]fixtureInteraction
+}
+
+// testFixtures is set when recording or replaying the acceptance tests.
+var testFixtures *fixtures
+
+// setupFixtures enables recording or replaying the acceptance tests as
+// configured by the environment. When replaying, placeholder credentials
+// are set if none are configured, as no API is called.
+func setupFixtures() error {
+	mode := os.Getenv("CLOUDSTACK_FIXTURES")
+	if mode == "" {
+		return nil
+	}
+	if mode != fixtureModeRecord && mode != fixtureModeReplay {
+		return fmt.Errorf("CLOUDSTACK_FIXTURES must be %q or %q, got: %q",
+			fixtureModeRecord, fixtureModeReplay, mode)
+	}
+
+	dir := os.Getenv("CLOUDSTACK_FIXTURES_DIR")
+	if dir == "" {
+		dir = defaultFixturesDir
+	}
+
+	if mode == fixtureModeReplay {
+		for k, v := range map[string]string{
+			"CLOUDSTACK_API_URL":    "http://cloudstack.invalid/client/api",
+			"CLOUDSTACK_API_KEY":    "replay",
+			"CLOUDSTACK_SECRET_KEY": "replay",
+		} {
+			if os.Getenv(k) == "" {
+				os.Setenv(k, v)
+			}
+		}
+	}
+
+	testFixtures = &fixtures{mode: mode, dir: dir, apiURL: os.Getenv("CLOUDSTACK_API_URL")}
+	wrapTransport = testFixtures.wrap
+
+	return nil
+}
+
+// teardownFixtures writes the calls recorded for the last test.
+func teardownFixtures() error {
+	if testFixtures == nil {
+		return nil
+	}
+
+	testFixtures.mu.Lock()
+	defer testFixtures.mu.Unlock()
+
+	return testFixtures.flush()
+}
+
+// start records or replays the API calls of the given test from now on.
+// When replaying, the test is skipped if no fixture was recorded for it.
+func (f *fixtures) start(t *testing.T) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	f.name = strings.Replace(t.Name(), "/", "_", -1)
+	f.recorded = nil
+	f.replay = nil
+
+	if f.mode != fixtureModeReplay {
+		return
+	}
+
+	b, err := ioutil.ReadFile(f.path())
+	if os.IsNotExist(err) {
+		f.name = ""
+		t.Skipf("No fixture recorded for %s in %s", t.Name(), f.dir)
+	}
+	if err != nil {
+		t.Fatalf("Error reading fixture %s: %s", f.path(), err)
+	}
+
+	var interactions []fixtureInteraction
+	if err := json.Unmarshal(b, &interactions); err != nil {
+		t.Fatalf("Error parsing fixture %s: %s", f.path(), err)
+	}
+
+	f.replay = make(map[string][]fixtureInteraction)
+	for _, i := range interactions {
+		f.replay[i.key()] = append(f.replay[i.key()], i)
+	}
+}
+
+// flush writes the calls recorded for the running test, even if there are
+// none so the test is replayed as well. The caller must hold the lock.
+func (f *fixtures) flush() error {
+	if f.mode != fixtureModeRecord || f.name == "" {
+		return nil
+	}
+
+	recorded := f.recorded
+	if recorded == nil {
+		recorded = []fixtureInteraction{}
+	}
+
+	b, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error encoding fixture %s: %s", f.path(), err)
+	}
+
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return fmt.Errorf("Error creating fixture directory %s: %s", f.dir, err)
+	}
+
+	if err := ioutil.WriteFile(f.path(), append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("Error writing fixture %s: %s", f.path(), err)
+	}
+
+	f.name = ""
+	f.recorded = nil
+
+	return nil
+}
+
+func (f *fixtures) path() string {
+	return filepath.Join(f.dir, f.name+".json")
+}
+
+// wrap returns the transport of a client, which records or replays the API
+// calls if the client uses the API of the acceptance tests.
+func (f *fixtures) wrap(c *Config, transport http.RoundTripper) http.RoundTripper {
+	if c.APIURL != f.apiURL {
+		return transport
+	}
+	return &fixtureTransport{fixtures: f, transport: transport}
+}
+
+// fixtureTransport is a http.RoundTripper that records the API calls sent
+// through it, or replays the recorded responses without calling the API.
+type fixtureTransport struct {
+	fixtures  *fixtures
+	transport http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	params, err := requestParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := fixtureInteraction{
+		Command: params.Get("command"),
+		Params:  make(map[string]string),
+	}
+	for k, v := range params {
+		if k != "command" && !fixtureVolatileParams[strings.ToLower(k)] {
+			interaction.Params[k] = strings.Join(v, ",")
+		}
+	}
+
+	if t.fixtures.mode == fixtureModeReplay {
+		return t.fixtures.next(req, &interaction)
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	interaction.Status = resp.StatusCode
+	interaction.Body = scrubFixtureBody(body)
+	t.fixtures.record(interaction)
+
+	return resp, nil
+}
+
+func (f *fixtures) record(interaction fixtureInteraction) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.name != "" {
+		f.recorded = append(f.recorded, interaction)
+	}
+}
+
+// next returns the next recorded response for the request. Calls with the
+// same parameters are replayed in the order they were recorded, and the last
+// response is repeated, as the number of times an async job is polled for
+// its result varies.
+func (f *fixtures) next(req *http.Request, interaction *fixtureInteraction) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := interaction.key()
+	recorded := f.replay[key]
+	if len(recorded) == 0 {
+		return nil, fmt.Errorf("No recorded response for %s in fixture %s", key, f.path())
+	}
+
+	i := recorded[0]
+	if len(recorded) > 1 {
+		f.replay[key] = recorded[1:]
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+		Body:          ioutil.NopCloser(strings.NewReader(i.Body)),
+		ContentLength: int64(len(i.Body)),
+		Request:       req,
+	}, nil
+}
+
+// scrubFixtureBody returns the body of a response with all credentials
+// replaced. Bodies that are not JSON are returned as is.
+func scrubFixtureBody(body []byte) string {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return string(body)
+	}
+
+	b, err := json.Marshal(scrubFixtureValue(v))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(b)
+}
+
+func scrubFixtureValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if fixtureScrubbedFields[strings.ToLower(k)] {
+				v[k] = "REDACTED"
+			} else {
+				v[k] = scrubFixtureValue(e)
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = scrubFixtureValue(e)
+		}
+	}
+	return v
+}
+
+func TestFixtureTransport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudstack-fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := newSimulator()
+	zoneid := s.ID("zone", "Sandbox-simulator")
+
+	newClient := func(f *fixtures) *cloudstack.CloudStackClient {
+		c := &Config{APIURL: s.APIURL()}
+		client := &http.Client{Transport: f.wrap(c, http.DefaultTransport)}
+		return cloudstack.NewAsyncClient(
+			c.APIURL, simulatorAPIKey, simulatorSecretKey, false, cloudstack.WithHTTPClient(client))
+	}
+
+	// Record the calls made to the simulator
+	f := &fixtures{mode: fixtureModeRecord, dir: dir, apiURL: s.APIURL()}
+	f.start(t)
+
+	cs := newClient(f)
+	for i := 0; i < 2; i++ {
+		if _, _, err := cs.Zone.GetZoneByID(zoneid); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := f.flush(); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, t.Name()+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), simulatorAPIKey) || strings.Contains(string(b), "signature") {
+		t.Fatalf("Expected credentials not to be recorded, got: %s", b)
+	}
+
+	// Replay the calls without the simulator, calls that were recorded
+	// less often than they are made repeat the last response
+	f = &fixtures{mode: fixtureModeReplay, dir: dir, apiURL: s.APIURL()}
+	f.start(t)
+
+	cs = newClient(f)
+	for i := 0; i < 3; i++ {
+		zone, _, err := cs.Zone.GetZoneByID(zoneid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if zone.Name != "Sandbox-simulator" {
+			t.Fatalf("Bad zone replayed: %+v", zone)
+		}
+	}
+
+	_, _, err = cs.Zone.GetZoneByName("Sandbox-simulator")
+	if err == nil || !strings.Contains(err.Error(), "No recorded response") {
+		t.Fatalf("Expected an error for a call that was not recorded, got: %v", err)
+	}
+}
+
+func TestScrubFixtureBody(t *testing.T) {
+	body := `{"listusersresponse":{"count":1,"user":[{"id":"1","apikey":"key","secretkey":"secret","size":10737418240}]}}`
+
+	scrubbed := scrubFixtureBody([]byte(body))
+	expected := `{"listusersresponse":{"count":1,"user":[{"apikey":"REDACTED","id":"1","secretkey":"REDACTED","size":10737418240}]}}`
+	if scrubbed != expected {
+		t.Fatalf("Bad scrubbed body: %s", scrubbed)
+	}
+
+	if scrubbed := scrubFixtureBody([]byte("not json")); scrubbed != "not json" {
+		t.Fatalf("Expected a body that is not JSON to be kept, got: %s", scrubbed)
+	}
+}
@@ -0,0 +1,319 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// idScope narrows the lookup of a name to a zone, domain and/or project.
+// Empty fields are ignored.
+type idScope struct {
+	zoneid    string
+	domainid  string
+	projectid string
+}
+
+// key returns the scope as a string that can be used as a cache key.
+func (s idScope) key() string {
+	if s == (idScope{}) {
+		return ""
+	}
+	return s.zoneid + "/" + s.domainid + "/" + s.projectid
+}
+
+// idCandidate is an object that matched the lookup of a name.
+type idCandidate struct {
+	id        string
+	name      string
+	zoneid    string
+	domainid  string
+	projectid string
+
+	// details describe where the object lives, so the user can tell
+	// candidates with the same name apart
+	details []string
+}
+
+// lookupID retrieves the ID of the named object of the given kind within
+// the given scope.
+func lookupID(cs *providerMeta, name string, value string, scope idScope) (string, error) {
+	log.Printf("[DEBUG] Retrieving ID of %s: %s", name, value)
+
+	candidates, err := listCandidates(cs, name, value, scope)
+	if err != nil {
+		return "", err
+	}
+
+	return selectID(name, value, scope, candidates)
+}
+
+// listCandidates lists the objects of the given kind that may match the
+// name. The zone and project are passed to the API where supported, as
+// project objects are not listed otherwise. The domain is only used to
+// select between candidates, as filtering on it would hide public objects.
+func listCandidates(cs *providerMeta, name string, value string, scope idScope) ([]idCandidate, error) {
+	var candidates []idCandidate
+
+	switch name {
+	case "domain":
+		p := cs.Domain.NewListDomainsParams()
+		p.SetListall(true)
+
+		// Domain names are only unique within their parent domain, so
+		// a full path like ROOT/customers/acme can be used as well
+		byPath := strings.Contains(value, "/")
+		if !byPath {
+			p.SetName(value)
+		}
+		l, err := cs.Domain.ListDomains(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range l.Domains {
+			c := idCandidate{id: v.Id, name: v.Name, details: describe("path", v.Path)}
+			if byPath {
+				c.name = v.Path
+			}
+			candidates = append(candidates, c)
+		}
+	case "disk_offering":
+		p := cs.DiskOffering.NewListDiskOfferingsParams()
+		p.SetName(value)
+		if scope.zoneid != "" {
+			p.SetZoneid(scope.zoneid)
+		}
+		l, err := cs.DiskOffering.ListDiskOfferings(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range l.DiskOfferings {
+			candidates = append(candidates, idCandidate{id: v.Id, name: v.Name,
+				zoneid: v.Zoneid, domainid: v.Domainid, details: describe("domain", v.Domain)})
+		}
+	case "service_offering":
+		p := cs.ServiceOffering.NewListServiceOfferingsParams()
+		p.SetName(value)
+		if scope.zoneid != "" {
+			p.SetZoneid(scope.zoneid)
+		}
+		l, err := cs.ServiceOffering.ListServiceOfferings(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range l.ServiceOfferings {
+			candidates = append(candidates, idCandidate{id: v.Id, name: v.Name,
+				zoneid: v.Zoneid, domainid: v.Domainid, details: describe("domain", v.Domain)})
+		}
+	case "network_offering":
+		p := cs.NetworkOffering.NewListNetworkOfferingsParams()
+		p.SetName(value)
+		if scope.zoneid != "" {
+			p.SetZoneid(scope.zoneid)
+		}
+		l, err := cs.NetworkOffering.ListNetworkOfferings(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range l.NetworkOfferings {
+			candidates = append(candidates, idCandidate{id: v.Id, name: v.Name,
+				zoneid: v.Zoneid, domainid: v.Domainid, details: describe("domain", v.Domain)})
+		}
+	case "project":
+		p := cs.Project.NewListProjectsParams()
+		p.SetName(value)
+		l, err := cs.Project.ListProjects(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range l.Projects {
+			candidates = append(candidates, idCandidate{id: v.Id, name: v.Name,
+				domainid: v.Domainid, details: describe("domain", v.Domain)})
+		}
+	case "vpc_offering":
+		p := cs.VPC.NewListVPCOfferingsParams()
+		p.SetName(value)
+		if scope.zoneid != "" {
+			p.SetZoneid(scope.zoneid)
+		}
+		l, err := cs.VPC.ListVPCOfferings(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range l.VPCOfferings {
+			candidates = append(candidates, idCandidate{id: v.Id, name: v.Name,
+				zoneid: v.Zoneid, domainid: v.Domainid, details: describe("domain", v.Domain)})
+		}
+	case "zone":
+		p := cs.Zone.NewListZonesParams()
+		p.SetName(value)
+		l, err := cs.Zone.ListZones(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range l.Zones {
+			candidates = append(candidates, idCandidate{id: v.Id, name: v.Name,
+				domainid: v.Domainid, details: describe("domain", v.Domain)})
+		}
+	case "os_type":
+		p := cs.GuestOS.NewListOsTypesParams()
+		p.SetDescription(value)
+		l, err := cs.GuestOS.ListOsTypes(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range l.OsTypes {
+			candidates = append(candidates, idCandidate{id: v.Id, name: v.Description})
+		}
+	case "template":
+		p := cs.Template.NewListTemplatesParams("executable")
+		p.SetName(value)
+		if scope.zoneid != "" {
+			p.SetZoneid(scope.zoneid)
+		}
+		if scope.projectid != "" {
+			p.SetProjectid(scope.projectid)
+		}
+		l, err := cs.Template.ListTemplates(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range l.Templates {
+			candidates = append(candidates, idCandidate{id: v.Id, name: v.Name,
+				zoneid: v.Zoneid, domainid: v.Domainid, projectid: v.Projectid,
+				details: describe("zone", v.Zonename, "domain", v.Domain, "account", v.Account, "project", v.Project)})
+		}
+	default:
+		return nil, fmt.Errorf("Unknown request: %s", name)
+	}
+
+	return candidates, nil
+}
+
+// selectID selects the ID of the candidate matching the name. Exact matches
+// are preferred over case insensitive matches, which are preferred over
+// partial matches. When more than one candidate matches, candidates within
+// the scope are preferred. An error listing the candidates is returned when
+// the name is still ambiguous.
+func selectID(name string, value string, scope idScope, candidates []idCandidate) (string, error) {
+	// The same object can be listed more than once, e.g. a template
+	// that is available in multiple zones
+	seen := make(map[string]bool)
+	var unique []idCandidate
+	for _, c := range candidates {
+		if !seen[c.id] {
+			seen[c.id] = true
+			unique = append(unique, c)
+		}
+	}
+
+	matches := filterCandidates(unique, func(c idCandidate) bool {
+		return c.name == value
+	})
+	if len(matches) == 0 {
+		matches = filterCandidates(unique, func(c idCandidate) bool {
+			return strings.EqualFold(c.name, value)
+		})
+	}
+	if len(matches) == 0 {
+		matches = filterCandidates(unique, func(c idCandidate) bool {
+			return strings.Contains(strings.ToLower(c.name), strings.ToLower(value))
+		})
+		if len(matches) == 1 {
+			log.Printf("[WARN] Using %s %s (%s) as partial match for: %s",
+				name, matches[0].name, matches[0].id, value)
+		}
+	}
+
+	if len(matches) > 1 && scope.zoneid != "" {
+		matches = preferCandidates(matches, func(c idCandidate) bool {
+			return c.zoneid == scope.zoneid
+		})
+	}
+	if len(matches) > 1 && scope.domainid != "" {
+		matches = preferCandidates(matches, func(c idCandidate) bool {
+			// Offerings can be available in multiple domains
+			for _, id := range strings.Split(c.domainid, ",") {
+				if id == scope.domainid {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	if len(matches) > 1 && scope.projectid != "" {
+		matches = preferCandidates(matches, func(c idCandidate) bool {
+			return c.projectid == scope.projectid
+		})
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("No match found for %s", value)
+	case 1:
+		return matches[0].id, nil
+	}
+
+	var ids []string
+	for _, c := range matches {
+		details := append([]string{fmt.Sprintf("name %q", c.name)}, c.details...)
+		ids = append(ids, fmt.Sprintf("%s (%s)", c.id, strings.Join(details, ", ")))
+	}
+
+	hint := "use one of the IDs or set the zone, domain or project to select one"
+	if name == "domain" {
+		hint = "use one of the IDs or the full path of the domain"
+	}
+
+	return "", fmt.Errorf("%d objects match %s, %s: %s",
+		len(matches), value, hint, strings.Join(ids, "; "))
+}
+
+func filterCandidates(candidates []idCandidate, f func(idCandidate) bool) []idCandidate {
+	var filtered []idCandidate
+	for _, c := range candidates {
+		if f(c) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// preferCandidates returns the candidates for which f returns true, or all
+// candidates if there are none.
+func preferCandidates(candidates []idCandidate, f func(idCandidate) bool) []idCandidate {
+	if preferred := filterCandidates(candidates, f); len(preferred) > 0 {
+		return preferred
+	}
+	return candidates
+}
+
+// describe formats the non-empty values of the given label and value pairs.
+func describe(pairs ...string) []string {
+	var details []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			details = append(details, pairs[i]+" "+pairs[i+1])
+		}
+	}
+	return details
+}
@@ -0,0 +1,200 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectID(t *testing.T) {
+	cases := []struct {
+		Value      string
+		Scope      idScope
+		Candidates []idCandidate
+		ID         string
+		Err        string
+	}{
+		{
+			Value: "small",
+			Candidates: []idCandidate{
+				{id: "1", name: "small"},
+				{id: "2", name: "Small"},
+				{id: "3", name: "small-2"},
+			},
+			ID: "1",
+		},
+		{
+			Value: "small",
+			Candidates: []idCandidate{
+				{id: "2", name: "Small"},
+				{id: "3", name: "small-2"},
+			},
+			ID: "2",
+		},
+		{
+			Value: "CentOS 7",
+			Candidates: []idCandidate{
+				{id: "4", name: "CentOS 7.8"},
+			},
+			ID: "4",
+		},
+		{
+			Value: "CentOS",
+			Candidates: []idCandidate{
+				{id: "4", name: "CentOS 7.8"},
+				{id: "5", name: "CentOS 8"},
+			},
+			Err: "2 objects match CentOS",
+		},
+		{
+			Value: "small",
+			Candidates: []idCandidate{
+				{id: "1", name: "small", zoneid: "z1"},
+				{id: "1", name: "small", zoneid: "z2"},
+			},
+			ID: "1",
+		},
+		{
+			Value: "small",
+			Scope: idScope{zoneid: "z2"},
+			Candidates: []idCandidate{
+				{id: "1", name: "small", zoneid: "z1"},
+				{id: "2", name: "small", zoneid: "z2"},
+			},
+			ID: "2",
+		},
+		{
+			Value: "small",
+			Scope: idScope{zoneid: "z3", domainid: "d2"},
+			Candidates: []idCandidate{
+				{id: "1", name: "small", domainid: "d1"},
+				{id: "2", name: "small", domainid: "d2,d3"},
+			},
+			ID: "2",
+		},
+		{
+			Value: "small",
+			Scope: idScope{projectid: "p1"},
+			Candidates: []idCandidate{
+				{id: "1", name: "small", projectid: "p1"},
+				{id: "2", name: "small"},
+			},
+			ID: "1",
+		},
+		{
+			Value: "small",
+			Scope: idScope{domainid: "d3"},
+			Candidates: []idCandidate{
+				{id: "1", name: "small", domainid: "d1", details: describe("domain", "ROOT")},
+				{id: "2", name: "small", domainid: "d2", details: describe("domain", "", "account", "admin")},
+			},
+			Err: `2 objects match small, use one of the IDs or set the zone, domain or project to select one: ` +
+				`1 (name "small", domain ROOT); 2 (name "small", account admin)`,
+		},
+		{
+			Value: "small",
+			Candidates: []idCandidate{
+				{id: "3", name: "large"},
+			},
+			Err: "No match found for small",
+		},
+	}
+
+	for i, tc := range cases {
+		id, err := selectID("disk_offering", tc.Value, tc.Scope, tc.Candidates)
+		if tc.Err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.Err) {
+				t.Fatalf("%d: expected error %q, got: %v", i, tc.Err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		if id != tc.ID {
+			t.Fatalf("%d: expected ID %s, got: %s", i, tc.ID, id)
+		}
+	}
+}
+
+func TestRetrieveScopedID(t *testing.T) {
+	s, cs := newSimulatorClient(t)
+	defer s.Close()
+
+	public := s.ID("serviceoffering", "Small Instance")
+	tenants := s.ID("domain", "tenants")
+	dedicated := s.Add("serviceoffering", simObject{
+		"name":        "Small Instance",
+		"displaytext": "Small Instance",
+		"cpunumber":   1,
+		"cpuspeed":    500,
+		"memory":      512,
+		"storagetype": "shared",
+		"domainid":    tenants,
+		"domain":      "tenants",
+	})
+
+	_, e := retrieveID(cs, "service_offering", "Small Instance")
+	if e == nil {
+		t.Fatal("Expected an error for an ambiguous name")
+	}
+	for _, id := range []string{public, dedicated} {
+		if !strings.Contains(e.Error().Error(), id) {
+			t.Fatalf("Expected error to list %s, got: %s", id, e.Error())
+		}
+	}
+
+	id, e := retrieveScopedID(cs, "service_offering", "Small Instance", idScope{domainid: tenants})
+	if e != nil {
+		t.Fatal(e.Error())
+	}
+	if id != dedicated {
+		t.Fatalf("Expected offering %s of the tenants domain, got: %s", dedicated, id)
+	}
+
+	// Names that are unique are resolved regardless of the scope
+	id, e = retrieveScopedID(cs, "service_offering", "Medium Instance", idScope{domainid: tenants})
+	if e != nil {
+		t.Fatal(e.Error())
+	}
+	if id != s.ID("serviceoffering", "Medium Instance") {
+		t.Fatalf("Bad ID of service offering Medium Instance: %s", id)
+	}
+
+	// Domain names are ambiguous as well, unless the full path is used
+	s.Add("domain", simObject{
+		"name":     "tenants",
+		"path":     "ROOT/other/tenants",
+		"parentid": s.ID("domain", "ROOT"),
+	})
+	if _, e := retrieveID(cs, "domain", "tenants"); e == nil ||
+		!strings.Contains(e.Error().Error(), "full path") {
+		t.Fatalf("Expected an error for an ambiguous domain, got: %v", e)
+	}
+
+	id, e = retrieveID(cs, "domain", "ROOT/tenants")
+	if e != nil {
+		t.Fatal(e.Error())
+	}
+	if id != tenants {
+		t.Fatalf("Bad ID of domain ROOT/tenants: %s", id)
+	}
+}
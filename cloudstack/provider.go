@@ -21,15 +21,44 @@ package cloudstack
 
 import (
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-ini/ini"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
+const (
+	// defaultConfigFile is the CloudMonkey config file used when no config
+	// file is configured
+	defaultConfigFile = "~/.cloudmonkey/config"
+
+	// defaultTimeout is the default timeout in seconds for async jobs
+	defaultTimeout = 900
+
+	// defaultRetryMaxAttempts is the default maximum number of attempts for
+	// API calls that fail with a transient error
+	defaultRetryMaxAttempts = 3
+
+	// defaultRetryWaitMin and defaultRetryWaitMax are the default bounds in
+	// seconds of the delay between two attempts
+	defaultRetryWaitMin = 1
+	defaultRetryWaitMax = 30
+
+	// defaultMaxRequestsPerSecond and defaultMaxConcurrentRequests are the
+	// default limits for the API calls made by the provider
+	defaultMaxRequestsPerSecond  = 10.0
+	defaultMaxConcurrentRequests = 10
+)
+
 // Provider returns a terraform.ResourceProvider.
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"api_url": {
 				Type:          schema.TypeString,
@@ -55,12 +84,14 @@ func Provider() terraform.ResourceProvider {
 			"config": {
 				Type:          schema.TypeString,
 				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("CLOUDSTACK_CONFIG", nil),
 				ConflictsWith: []string{"api_url", "api_key", "secret_key"},
 			},
 
 			"profile": {
 				Type:          schema.TypeString,
 				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("CLOUDSTACK_PROFILE", nil),
 				ConflictsWith: []string{"api_url", "api_key", "secret_key"},
 			},
 
@@ -72,19 +103,107 @@ func Provider() terraform.ResourceProvider {
 
 			"timeout": {
 				Type:        schema.TypeInt,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_TIMEOUT", 900),
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_TIMEOUT", nil),
+			},
+
+			"ca_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_CA_FILE", nil),
+			},
+
+			"client_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_CLIENT_CERT_FILE", nil),
+			},
+
+			"client_key_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_CLIENT_KEY_FILE", nil),
+			},
+
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_INSECURE", false),
+			},
+
+			"http_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_HTTP_PROXY", nil),
+			},
+
+			"retry_max_attempts": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_RETRY_MAX_ATTEMPTS", defaultRetryMaxAttempts),
+			},
+
+			"retry_wait_min": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_RETRY_WAIT_MIN", defaultRetryWaitMin),
+			},
+
+			"retry_wait_max": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_RETRY_WAIT_MAX", defaultRetryWaitMax),
+			},
+
+			"retry_error_codes": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+
+			"max_requests_per_second": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_MAX_REQUESTS_PER_SECOND", defaultMaxRequestsPerSecond),
+			},
+
+			"max_concurrent_requests": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_MAX_CONCURRENT_REQUESTS", defaultMaxConcurrentRequests),
+			},
+
+			"validate_references_at_plan": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_VALIDATE_REFERENCES_AT_PLAN", false),
+			},
+
+			"api_log_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CLOUDSTACK_API_LOG_FILE", ""),
 			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"cloudstack_template": dataSourceCloudstackTemplate(),
+			"cloudstack_disk_offering":    dataSourceCloudstackDiskOffering(),
+			"cloudstack_instance":         dataSourceCloudstackInstance(),
+			"cloudstack_ipaddress":        dataSourceCloudstackIPAddress(),
+			"cloudstack_network":          dataSourceCloudstackNetwork(),
+			"cloudstack_service_offering": dataSourceCloudstackServiceOffering(),
+			"cloudstack_template":         dataSourceCloudstackTemplate(),
+			"cloudstack_volume":           dataSourceCloudstackVolume(),
+			"cloudstack_vpc":              dataSourceCloudstackVPC(),
+			"cloudstack_zone":             dataSourceCloudstackZone(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
+			"cloudstack_account":              resourceCloudStackAccount(),
 			"cloudstack_affinity_group":       resourceCloudStackAffinityGroup(),
 			"cloudstack_autoscale_vm_profile": resourceCloudStackAutoScaleVMProfile(),
 			"cloudstack_disk":                 resourceCloudStackDisk(),
+			"cloudstack_domain":               resourceCloudStackDomain(),
 			"cloudstack_egress_firewall":      resourceCloudStackEgressFirewall(),
 			"cloudstack_firewall":             resourceCloudStackFirewall(),
 			"cloudstack_instance":             resourceCloudStackInstance(),
@@ -96,6 +215,7 @@ func Provider() terraform.ResourceProvider {
 			"cloudstack_nic":                  resourceCloudStackNIC(),
 			"cloudstack_port_forward":         resourceCloudStackPortForward(),
 			"cloudstack_private_gateway":      resourceCloudStackPrivateGateway(),
+			"cloudstack_project":              resourceCloudStackProject(),
 			"cloudstack_secondary_ipaddress":  resourceCloudStackSecondaryIPAddress(),
 			"cloudstack_security_group":       resourceCloudStackSecurityGroup(),
 			"cloudstack_security_group_rule":  resourceCloudStackSecurityGroupRule(),
@@ -103,60 +223,208 @@ func Provider() terraform.ResourceProvider {
 			"cloudstack_static_nat":           resourceCloudStackStaticNAT(),
 			"cloudstack_static_route":         resourceCloudStackStaticRoute(),
 			"cloudstack_template":             resourceCloudStackTemplate(),
+			"cloudstack_user":                 resourceCloudStackUser(),
 			"cloudstack_vpc":                  resourceCloudStackVPC(),
 			"cloudstack_vpn_connection":       resourceCloudStackVPNConnection(),
 			"cloudstack_vpn_customer_gateway": resourceCloudStackVPNCustomerGateway(),
 			"cloudstack_vpn_gateway":          resourceCloudStackVPNGateway(),
 		},
+	}
 
-		ConfigureFunc: providerConfigure,
+	p.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		meta, err := providerConfigure(d)
+		if err != nil {
+			return nil, err
+		}
+
+		// Close the API log file once the provider is stopped. Otherwise
+		// it is closed when the provider process exits.
+		if meta.apiLog != nil {
+			go func() {
+				<-p.StopContext().Done()
+				meta.close()
+			}()
+		}
+
+		return meta, nil
 	}
+
+	suppressSameReferences(p)
+
+	return p
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(d *schema.ResourceData) (*providerMeta, error) {
+	cfg, err := providerConfig(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.newMeta()
+}
+
+// providerConfig builds the client configuration from either the static
+// API credentials or a CloudMonkey config file profile.
+func providerConfig(d *schema.ResourceData) (*Config, error) {
 	apiURL, apiURLOK := d.GetOk("api_url")
 	apiKey, apiKeyOK := d.GetOk("api_key")
 	secretKey, secretKeyOK := d.GetOk("secret_key")
 	config, configOK := d.GetOk("config")
 	profile, profileOK := d.GetOk("profile")
 
+	cfg := &Config{
+		HTTPGETOnly: d.Get("http_get_only").(bool),
+		Timeout:     defaultTimeout,
+	}
+
 	switch {
 	case apiURLOK, apiKeyOK, secretKeyOK:
 		if !(apiURLOK && apiKeyOK && secretKeyOK) {
 			return nil, errors.New("'api_url', 'api_key' and 'secret_key' should all have values")
 		}
-	case configOK, profileOK:
-		if !(configOK && profileOK) {
-			return nil, errors.New("'config' and 'profile' should both have a value")
-		}
+
+		cfg.APIURL = apiURL.(string)
+		cfg.APIKey = apiKey.(string)
+		cfg.SecretKey = secretKey.(string)
+
 	default:
-		return nil, errors.New(
-			"either 'api_url', 'api_key' and 'secret_key' or 'config' and 'profile' should have values")
+		if !configOK {
+			config = defaultConfigFile
+
+			// Only fall back to the default config file if it exists, or
+			// if a profile is explicitly requested
+			if _, err := os.Stat(expandPath(defaultConfigFile)); err != nil && !profileOK {
+				return nil, errors.New(
+					"either 'api_url', 'api_key' and 'secret_key' or 'config' and 'profile' should have values")
+			}
+		}
+
+		if err := loadProfile(cfg, expandPath(config.(string)), profile.(string)); err != nil {
+			return nil, err
+		}
+	}
+
+	if timeout, ok := d.GetOk("timeout"); ok {
+		cfg.Timeout = int64(timeout.(int))
 	}
 
-	if configOK && profileOK {
-		cfg, err := ini.Load(config.(string))
+	if caFile, ok := d.GetOk("ca_file"); ok {
+		cfg.CAFile = expandPath(caFile.(string))
+	}
+
+	if certFile, ok := d.GetOk("client_cert_file"); ok {
+		cfg.ClientCertFile = expandPath(certFile.(string))
+	}
+
+	if keyFile, ok := d.GetOk("client_key_file"); ok {
+		cfg.ClientKeyFile = expandPath(keyFile.(string))
+	}
+
+	// Disabling certificate verification in the provider configuration
+	// overrides the 'verifysslcert' setting of the profile
+	if d.Get("insecure").(bool) {
+		cfg.Insecure = true
+	}
+
+	if httpProxy, ok := d.GetOk("http_proxy"); ok {
+		cfg.HTTPProxy = httpProxy.(string)
+	}
+
+	cfg.RetryMaxAttempts = d.Get("retry_max_attempts").(int)
+	cfg.RetryWaitMin = time.Duration(d.Get("retry_wait_min").(int)) * time.Second
+	cfg.RetryWaitMax = time.Duration(d.Get("retry_wait_max").(int)) * time.Second
+
+	if cfg.RetryWaitMax < cfg.RetryWaitMin {
+		return nil, errors.New("'retry_wait_max' should not be less than 'retry_wait_min'")
+	}
+
+	for _, code := range d.Get("retry_error_codes").([]interface{}) {
+		cfg.RetryErrorCodes = append(cfg.RetryErrorCodes, code.(int))
+	}
+
+	cfg.MaxRequestsPerSecond = d.Get("max_requests_per_second").(float64)
+	cfg.MaxConcurrentRequests = d.Get("max_concurrent_requests").(int)
+
+	if cfg.MaxRequestsPerSecond < 0 || cfg.MaxConcurrentRequests < 0 {
+		return nil, errors.New("'max_requests_per_second' and 'max_concurrent_requests' should not be negative")
+	}
+
+	cfg.ValidateReferencesAtPlan = d.Get("validate_references_at_plan").(bool)
+	cfg.APILogFile = d.Get("api_log_file").(string)
+
+	return cfg, nil
+}
+
+// loadProfile reads the API URL, credentials, SSL verification and timeout
+// from the given profile of a CloudMonkey config file. If no profile is
+// given, the default profile of the config file is used.
+func loadProfile(cfg *Config, config string, profile string) error {
+	f, err := ini.Load(config)
+	if err != nil {
+		return fmt.Errorf("Error loading config file %s: %s", config, err)
+	}
+
+	// CloudMonkey stores the name of the default profile in the core section
+	if profile == "" {
+		profile = f.Section("core").Key("profile").String()
+	}
+
+	// Fallback to the default (unnamed) section of the config file
+	if profile == "" {
+		profile = ini.DEFAULT_SECTION
+	}
+
+	section, err := f.GetSection(profile)
+	if err != nil {
+		return fmt.Errorf("Error loading profile %s from config file %s: %s", profile, config, err)
+	}
+
+	// Keys that are not set in the profile are read from the default section
+	key := func(name string) *ini.Key {
+		if section.HasKey(name) {
+			return section.Key(name)
+		}
+		return f.Section(ini.DEFAULT_SECTION).Key(name)
+	}
+
+	cfg.APIURL = key("url").String()
+	cfg.APIKey = key("apikey").String()
+	cfg.SecretKey = key("secretkey").String()
+
+	if cfg.APIURL == "" || cfg.APIKey == "" || cfg.SecretKey == "" {
+		return fmt.Errorf(
+			"Profile %s in config file %s should have a 'url', 'apikey' and 'secretkey'", profile, config)
+	}
+
+	if v := key("verifysslcert").String(); v != "" {
+		verify, err := strconv.ParseBool(v)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("Invalid value for 'verifysslcert' in profile %s: %s", profile, v)
 		}
+		cfg.Insecure = !verify
+	}
 
-		section, err := cfg.GetSection(profile.(string))
+	if v := key("timeout").String(); v != "" {
+		timeout, err := strconv.ParseInt(v, 10, 64)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("Invalid value for 'timeout' in profile %s: %s", profile, v)
 		}
+		cfg.Timeout = timeout
+	}
 
-		apiURL = section.Key("url").String()
-		apiKey = section.Key("apikey").String()
-		secretKey = section.Key("secretkey").String()
+	return nil
+}
+
+// expandPath expands a leading ~ to the home directory of the current user.
+func expandPath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
 	}
 
-	cfg := Config{
-		APIURL:      apiURL.(string),
-		APIKey:      apiKey.(string),
-		SecretKey:   secretKey.(string),
-		HTTPGETOnly: d.Get("http_get_only").(bool),
-		Timeout:     int64(d.Get("timeout").(int)),
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
 	}
 
-	return cfg.NewClient()
+	return filepath.Join(home, path[1:])
 }
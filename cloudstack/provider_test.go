@@ -20,9 +20,15 @@
 package cloudstack
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -39,6 +45,36 @@ func init() {
 	}
 }
 
+func TestMain(m *testing.M) {
+	// Run the acceptance tests against an in-process simulator when
+	// no CloudStack API is configured and no fixtures are replayed
+	var s *simulator
+	if os.Getenv("CLOUDSTACK_API_URL") == "" && os.Getenv("CLOUDSTACK_FIXTURES") != fixtureModeReplay {
+		s = newSimulator()
+		os.Setenv("CLOUDSTACK_API_URL", s.APIURL())
+		os.Setenv("CLOUDSTACK_API_KEY", simulatorAPIKey)
+		os.Setenv("CLOUDSTACK_SECRET_KEY", simulatorSecretKey)
+	}
+
+	if err := setupFixtures(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	if err := teardownFixtures(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		code = 1
+	}
+
+	if s != nil {
+		s.Close()
+	}
+
+	os.Exit(code)
+}
+
 func TestProvider(t *testing.T) {
 	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
@@ -81,4 +117,341 @@ func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("CLOUDSTACK_SECRET_KEY"); v == "" {
 		t.Fatal("CLOUDSTACK_SECRET_KEY must be set for acceptance tests")
 	}
+
+	if testFixtures != nil {
+		testFixtures.start(t)
+	}
+}
+
+const testCloudMonkeyConfig = `
+[core]
+profile = sandbox
+
+[sandbox]
+url = http://localhost:8080/client/api
+apikey = sandbox-api-key
+secretkey = sandbox-secret-key
+verifysslcert = true
+timeout = 3600
+
+[other]
+url = http://other:8080/client/api
+apikey = other-api-key
+secretkey = other-secret-key
+
+[lab]
+url = https://lab:8443/client/api
+apikey = lab-api-key
+secretkey = lab-secret-key
+verifysslcert = false
+`
+
+// testProviderConfig returns the client configuration for the given raw
+// provider configuration and environment, ignoring any credentials that are
+// already set in the environment.
+func testProviderConfig(t *testing.T, raw map[string]interface{}, env map[string]string) (*Config, error) {
+	for _, k := range []string{
+		"CLOUDSTACK_API_URL", "CLOUDSTACK_API_KEY", "CLOUDSTACK_SECRET_KEY",
+		"CLOUDSTACK_CONFIG", "CLOUDSTACK_PROFILE", "CLOUDSTACK_TIMEOUT",
+		"CLOUDSTACK_CA_FILE", "CLOUDSTACK_CLIENT_CERT_FILE", "CLOUDSTACK_CLIENT_KEY_FILE",
+		"CLOUDSTACK_INSECURE", "CLOUDSTACK_HTTP_PROXY", "CLOUDSTACK_RETRY_MAX_ATTEMPTS",
+		"CLOUDSTACK_RETRY_WAIT_MIN", "CLOUDSTACK_RETRY_WAIT_MAX",
+		"CLOUDSTACK_MAX_REQUESTS_PER_SECOND", "CLOUDSTACK_MAX_CONCURRENT_REQUESTS",
+		"CLOUDSTACK_VALIDATE_REFERENCES_AT_PLAN", "CLOUDSTACK_API_LOG_FILE",
+	} {
+		if v, ok := os.LookupEnv(k); ok {
+			defer os.Setenv(k, v)
+		} else {
+			defer os.Unsetenv(k)
+		}
+
+		if v, ok := env[k]; ok {
+			os.Setenv(k, v)
+		} else {
+			os.Unsetenv(k)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw)
+
+	return providerConfig(d)
+}
+
+func TestProviderConfig_profile(t *testing.T) {
+	home, err := ioutil.TempDir("", "cloudmonkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	if err := os.Mkdir(filepath.Join(home, ".cloudmonkey"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(home, ".cloudmonkey", "config")
+	if err := ioutil.WriteFile(config, []byte(testCloudMonkeyConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", home)
+
+	cases := []struct {
+		Raw      map[string]interface{}
+		APIURL   string
+		Insecure bool
+		Timeout  int64
+		Err      string
+	}{
+		// The default profile of the default config file
+		{
+			Raw:     map[string]interface{}{},
+			APIURL:  "http://localhost:8080/client/api",
+			Timeout: 3600,
+		},
+		// An explicit profile with an expanded config path
+		{
+			Raw:     map[string]interface{}{"config": "~/.cloudmonkey/config", "profile": "other"},
+			APIURL:  "http://other:8080/client/api",
+			Timeout: 900,
+		},
+		// The timeout argument overrides the timeout of the profile
+		{
+			Raw:     map[string]interface{}{"config": config, "timeout": 60},
+			APIURL:  "http://localhost:8080/client/api",
+			Timeout: 60,
+		},
+		// A profile that disables certificate verification
+		{
+			Raw:      map[string]interface{}{"config": config, "profile": "lab"},
+			APIURL:   "https://lab:8443/client/api",
+			Insecure: true,
+			Timeout:  900,
+		},
+		// The insecure argument overrides the profile
+		{
+			Raw:      map[string]interface{}{"config": config, "insecure": true},
+			APIURL:   "http://localhost:8080/client/api",
+			Insecure: true,
+			Timeout:  3600,
+		},
+		{
+			Raw: map[string]interface{}{"config": config, "profile": "unknown"},
+			Err: "Error loading profile unknown",
+		},
+		{
+			Raw: map[string]interface{}{"config": "~/unknown"},
+			Err: "Error loading config file",
+		},
+	}
+
+	for i, tc := range cases {
+		cfg, err := testProviderConfig(t, tc.Raw, nil)
+		if tc.Err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.Err) {
+				t.Fatalf("%d: expected error containing %q, got: %v", i, tc.Err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+
+		if cfg.APIURL != tc.APIURL {
+			t.Fatalf("%d: bad API URL: %s", i, cfg.APIURL)
+		}
+		if cfg.Insecure != tc.Insecure {
+			t.Fatalf("%d: bad insecure mode: %t", i, cfg.Insecure)
+		}
+		if cfg.Timeout != tc.Timeout {
+			t.Fatalf("%d: bad timeout: %d", i, cfg.Timeout)
+		}
+	}
+}
+
+func TestProviderConfig_env(t *testing.T) {
+	f, err := ioutil.TempFile("", "cloudmonkey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(testCloudMonkeyConfig); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	cfg, err := testProviderConfig(t, map[string]interface{}{}, map[string]string{
+		"CLOUDSTACK_CONFIG":  f.Name(),
+		"CLOUDSTACK_PROFILE": "other",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cfg.APIKey != "other-api-key" || cfg.SecretKey != "other-secret-key" {
+		t.Fatalf("Bad credentials: %s/%s", cfg.APIKey, cfg.SecretKey)
+	}
+
+	// Static credentials take precedence over the profile
+	cfg, err = testProviderConfig(t, map[string]interface{}{}, map[string]string{
+		"CLOUDSTACK_API_URL":    "http://localhost:8080/client/api",
+		"CLOUDSTACK_API_KEY":    "api-key",
+		"CLOUDSTACK_SECRET_KEY": "secret-key",
+		"CLOUDSTACK_PROFILE":    "other",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cfg.APIKey != "api-key" || cfg.Timeout != 900 {
+		t.Fatalf("Bad configuration: %#v", cfg)
+	}
+}
+
+func TestProviderConfig_retry(t *testing.T) {
+	raw := map[string]interface{}{
+		"api_url":    "http://localhost:8080/client/api",
+		"api_key":    "api-key",
+		"secret_key": "secret-key",
+	}
+
+	cfg, err := testProviderConfig(t, raw, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cfg.RetryMaxAttempts != 3 || cfg.RetryWaitMin != time.Second || cfg.RetryWaitMax != 30*time.Second {
+		t.Fatalf("Bad default retry policy: %#v", cfg)
+	}
+
+	raw["retry_error_codes"] = []interface{}{431}
+	raw["http_proxy"] = "http://proxy:3128"
+
+	cfg, err = testProviderConfig(t, raw, map[string]string{
+		"CLOUDSTACK_RETRY_MAX_ATTEMPTS": "5",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cfg.RetryMaxAttempts != 5 || len(cfg.RetryErrorCodes) != 1 || cfg.RetryErrorCodes[0] != 431 {
+		t.Fatalf("Bad retry policy: %#v", cfg)
+	}
+	if cfg.HTTPProxy != "http://proxy:3128" {
+		t.Fatalf("Bad HTTP proxy: %s", cfg.HTTPProxy)
+	}
+
+	raw["retry_wait_min"] = 60
+	if _, err := testProviderConfig(t, raw, nil); err == nil {
+		t.Fatal("Expected an error for a minimum wait larger than the maximum wait")
+	}
+}
+
+func TestProviderConfig_limits(t *testing.T) {
+	raw := map[string]interface{}{
+		"api_url":    "http://localhost:8080/client/api",
+		"api_key":    "api-key",
+		"secret_key": "secret-key",
+	}
+
+	cfg, err := testProviderConfig(t, raw, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cfg.MaxRequestsPerSecond != 10 || cfg.MaxConcurrentRequests != 10 {
+		t.Fatalf("Bad default limits: %#v", cfg)
+	}
+
+	cfg, err = testProviderConfig(t, raw, map[string]string{
+		"CLOUDSTACK_MAX_REQUESTS_PER_SECOND": "0.5",
+		"CLOUDSTACK_MAX_CONCURRENT_REQUESTS": "0",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cfg.MaxRequestsPerSecond != 0.5 || cfg.MaxConcurrentRequests != 0 {
+		t.Fatalf("Bad limits: %#v", cfg)
+	}
+
+	raw["max_concurrent_requests"] = -1
+	if _, err := testProviderConfig(t, raw, nil); err == nil {
+		t.Fatal("Expected an error for a negative limit")
+	}
+}
+
+func TestProviderConfig_validateReferences(t *testing.T) {
+	raw := map[string]interface{}{
+		"api_url":    "http://localhost:8080/client/api",
+		"api_key":    "api-key",
+		"secret_key": "secret-key",
+	}
+
+	cfg, err := testProviderConfig(t, raw, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cfg.ValidateReferencesAtPlan {
+		t.Fatal("Expected references not to be validated at plan by default")
+	}
+
+	cfg, err = testProviderConfig(t, raw, map[string]string{
+		"CLOUDSTACK_VALIDATE_REFERENCES_AT_PLAN": "true",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	cs, err := cfg.newMeta()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !cs.config.ValidateReferencesAtPlan {
+		t.Fatal("Expected the client configuration to validate references at plan")
+	}
+}
+
+func TestProvider_stopClosesAPILogFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudstack-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"api_url":      "http://localhost:8080/client/api",
+		"api_key":      "api-key",
+		"secret_key":   "secret-key",
+		"api_log_file": filepath.Join(dir, "api.log"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := Provider().(*schema.Provider)
+	if err := p.Configure(terraform.NewResourceConfig(raw)); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	cs := p.Meta().(*providerMeta)
+	if cs.apiLog == nil {
+		t.Fatal("Expected the API log file to be opened")
+	}
+
+	if err := p.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is closed in the background
+	for i := 0; i < 100; i++ {
+		if _, err := cs.apiLog.Write([]byte("\n")); err != nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("Expected the API log file to be closed once the provider is stopped")
 }
@@ -0,0 +1,246 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// validateReferences returns a CustomizeDiffFunc that resolves the given
+// references during plan, if enabled in the provider configuration. A
+// reference is an argument that refers to another CloudStack object by
+// either its name or ID, where the key is also the name used by retrieveID.
+func validateReferences(keys ...string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, meta interface{}) error {
+		cs := meta.(*providerMeta)
+		if !cs.config.ValidateReferencesAtPlan {
+			return nil
+		}
+
+		scope, err := diffScope(cs, d)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if _, err := checkReference(cs, d, key, referenceScope(key, scope)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// diffScope returns the scope used to resolve the references of a diff, which
+// consists of the zone, domain and project of the resource as far as they are
+// set and known.
+func diffScope(cs *providerMeta, d *schema.ResourceDiff) (idScope, error) {
+	var scope idScope
+
+	if zone, ok := knownValue(d, "zone"); ok {
+		zoneid, e := retrieveID(cs, "zone", zone)
+		if e != nil {
+			return scope, e.Error()
+		}
+		scope.zoneid = zoneid
+	}
+
+	if domain, ok := knownValue(d, "domain"); ok {
+		domainid, e := retrieveID(cs, "domain", domain)
+		if e != nil {
+			return scope, e.Error()
+		}
+		scope.domainid = domainid
+	}
+
+	if project, ok := knownValue(d, "project"); ok {
+		projectid, e := retrieveScopedID(cs, "project", project, idScope{domainid: scope.domainid})
+		if e != nil {
+			return scope, e.Error()
+		}
+		scope.projectid = projectid
+	}
+
+	return scope, nil
+}
+
+// knownValue returns the new value of a string argument of a diff, if it is
+// set and known.
+func knownValue(d *schema.ResourceDiff, key string) (string, bool) {
+	v, ok := d.Get(key).(string)
+	return v, ok && v != "" && d.NewValueKnown(key)
+}
+
+// referenceScope returns the part of the scope of a resource that is used to
+// resolve the given reference. Zones are global and projects only live within
+// a domain.
+func referenceScope(key string, scope idScope) idScope {
+	switch key {
+	case "zone":
+		return idScope{}
+	case "project":
+		return idScope{domainid: scope.domainid}
+	}
+	return scope
+}
+
+// checkReference resolves a new or changed reference within the given scope
+// and returns its ID. An empty ID is returned if the reference is not set,
+// unchanged or not known yet.
+func checkReference(cs *providerMeta, d *schema.ResourceDiff, key string, scope idScope) (string, error) {
+	if !d.NewValueKnown(key) || !d.HasChange(key) {
+		return "", nil
+	}
+
+	n := d.Get(key).(string)
+	if n == "" {
+		return "", nil
+	}
+
+	id, e := retrieveScopedID(cs, key, n, scope)
+	if e != nil {
+		return "", e.Error()
+	}
+
+	return id, nil
+}
+
+// suppressSameReferences suppresses a change of the project, VPC offering or
+// zone of the resources that validate their references, if the change is
+// from a name to the ID of the same object (or the other way around). Such a
+// change would otherwise replace the resource without any real change. The
+// names are resolved with the client of the configured provider.
+func suppressSameReferences(p *schema.Provider) {
+	for _, r := range p.ResourcesMap {
+		if r.CustomizeDiff == nil {
+			continue
+		}
+
+		for _, key := range []string{"project", "vpc_offering", "zone"} {
+			if s, ok := r.Schema[key]; ok && s.ForceNew && s.DiffSuppressFunc == nil {
+				s.DiffSuppressFunc = sameReference(key, p.Meta)
+			}
+		}
+	}
+}
+
+// sameReference returns a SchemaDiffSuppressFunc that reports whether the old
+// and new value of a reference refer to the same object.
+func sameReference(key string, meta func() interface{}) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		cs, ok := meta().(*providerMeta)
+		if !ok || !cs.config.ValidateReferencesAtPlan {
+			return false
+		}
+
+		// Only a change between a name and an ID can refer to the same object
+		if d.Id() == "" || old == "" || new == "" || cloudstack.IsID(old) == cloudstack.IsID(new) {
+			return false
+		}
+
+		var zoneid string
+		if key != "zone" {
+			id, e := retrieveID(cs, "zone", d.Get("zone").(string))
+			if e != nil {
+				return false
+			}
+			zoneid = id
+		}
+
+		scope, err := lookupScope(cs, d, zoneid)
+		if err != nil {
+			return false
+		}
+		scope = referenceScope(key, scope)
+
+		oldID, e := retrieveScopedID(cs, key, old, scope)
+		if e != nil {
+			return false
+		}
+
+		newID, e := retrieveScopedID(cs, key, new, scope)
+		if e != nil {
+			return false
+		}
+
+		return oldID == newID
+	}
+}
+
+// resourceCloudStackInstanceCustomizeDiff resolves the references of an
+// instance and rejects a root disk size smaller than the size of the
+// template.
+func resourceCloudStackInstanceCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	cs := meta.(*providerMeta)
+	if !cs.config.ValidateReferencesAtPlan {
+		return nil
+	}
+
+	// The service offering and template are resolved within the zone,
+	// domain and project of the instance
+	scope, err := diffScope(cs, d)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range []string{"zone", "project", "service_offering"} {
+		if _, err := checkReference(cs, d, key, referenceScope(key, scope)); err != nil {
+			return err
+		}
+	}
+
+	if !d.HasChange("template") && !d.HasChange("root_disk_size") {
+		return nil
+	}
+
+	if !d.NewValueKnown("zone") || !d.NewValueKnown("template") {
+		return nil
+	}
+
+	templateid, e := retrieveScopedID(cs, "template", d.Get("template").(string), scope)
+	if e != nil {
+		return e.Error()
+	}
+
+	rootdisksize, ok := d.GetOk("root_disk_size")
+	if !ok || !d.NewValueKnown("root_disk_size") {
+		return nil
+	}
+
+	t, _, err := cs.Template.GetTemplateByID(
+		templateid, "executable", cloudstack.WithZone(scope.zoneid), cloudstack.WithProject(scope.projectid))
+	if err != nil {
+		return fmt.Errorf("Error retrieving template %s: %s", d.Get("template").(string), err)
+	}
+
+	// CloudStack rejects the deployment of a root disk smaller than the
+	// template, so fail the plan instead
+	if int64(rootdisksize.(int))<<30 < t.Size {
+		return fmt.Errorf(
+			"root_disk_size of %d GiB is smaller than the size of template %s (%d GiB)",
+			rootdisksize.(int), d.Get("template").(string), t.Size>>30)
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceCloudStackAccount() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackAccountCreate,
+		Read:   resourceCloudStackAccountRead,
+		Update: resourceCloudStackAccountUpdate,
+		Delete: resourceCloudStackAccountDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"account_type": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ForceNew:     true,
+				ValidateFunc: validation.IntInSlice([]int{0, 1, 2}),
+			},
+
+			"role_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"network_domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"password": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+
+			"email": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"first_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"last_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"timezone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"user_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackAccountCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	username := d.Get("username").(string)
+
+	// Create a new parameter struct
+	p := cs.Account.NewCreateAccountParams(
+		d.Get("email").(string),
+		d.Get("first_name").(string),
+		d.Get("last_name").(string),
+		d.Get("password").(string),
+		username,
+	)
+
+	// The account name defaults to the username of the first user
+	if name, ok := d.GetOk("name"); ok {
+		p.SetAccount(name.(string))
+	}
+
+	p.SetAccounttype(d.Get("account_type").(int))
+
+	if roleid, ok := d.GetOk("role_id"); ok {
+		p.SetRoleid(roleid.(string))
+	}
+
+	// If there is a domain supplied, we retrieve and set the domain id
+	if domain, ok := d.GetOk("domain"); ok {
+		domainid, e := retrieveID(cs, "domain", domain.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetDomainid(domainid)
+	}
+
+	if networkDomain, ok := d.GetOk("network_domain"); ok {
+		p.SetNetworkdomain(networkDomain.(string))
+	}
+
+	if timezone, ok := d.GetOk("timezone"); ok {
+		p.SetTimezone(timezone.(string))
+	}
+
+	log.Printf("[DEBUG] Creating account for user %s", username)
+	r, err := cs.Account.CreateAccount(p)
+	if err != nil {
+		return fmt.Errorf("Error creating account for user %s: %s", username, err)
+	}
+
+	d.SetId(r.Id)
+
+	for _, u := range r.User {
+		if u.Username == username {
+			d.Set("user_id", u.Id)
+			break
+		}
+	}
+
+	return resourceCloudStackAccountRead(d, meta)
+}
+
+func resourceCloudStackAccountRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	// Get the account details
+	a, count, err := cs.Account.GetAccountByID(d.Id())
+	if err != nil {
+		if count == 0 || isNotFound(err) {
+			log.Printf("[DEBUG] Account %s does no longer exist", d.Get("name").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("name", a.Name)
+	d.Set("account_type", a.Accounttype)
+	d.Set("role_id", a.Roleid)
+	d.Set("network_domain", a.Networkdomain)
+	d.Set("state", a.State)
+
+	setDomain(d, "domain", a.Domain, a.Domainid)
+
+	// When importing an account, the first user of the account is managed
+	userid := d.Get("user_id").(string)
+	if userid == "" && len(a.User) > 0 {
+		userid = a.User[0].Id
+	}
+
+	if userid == "" {
+		return nil
+	}
+
+	// Get the user details
+	u, count, err := cs.User.GetUserByID(userid)
+	if err != nil {
+		if count == 0 || isNotFound(err) {
+			log.Printf("[DEBUG] User %s of account %s does no longer exist", userid, a.Name)
+			d.Set("user_id", "")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("user_id", u.Id)
+	d.Set("username", u.Username)
+	d.Set("email", u.Email)
+	d.Set("first_name", u.Firstname)
+	d.Set("last_name", u.Lastname)
+	d.Set("timezone", u.Timezone)
+
+	return nil
+}
+
+func resourceCloudStackAccountUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	name := d.Get("name").(string)
+
+	// Check if the name or network domain is changed
+	if d.HasChange("name") || d.HasChange("network_domain") {
+		o, _ := d.GetChange("name")
+
+		// Create a new parameter struct
+		p := cs.Account.NewUpdateAccountParams()
+		p.SetId(d.Id())
+
+		if d.HasChange("name") {
+			p.SetNewname(name)
+		}
+
+		if d.HasChange("network_domain") {
+			p.SetNetworkdomain(d.Get("network_domain").(string))
+		}
+
+		// Update the account
+		_, err := cs.Account.UpdateAccount(p)
+		if err != nil {
+			return fmt.Errorf("Error updating account %s: %s", o.(string), err)
+		}
+	}
+
+	// Check if any of the user details are changed
+	if d.HasChange("password") || d.HasChange("email") || d.HasChange("first_name") ||
+		d.HasChange("last_name") || d.HasChange("timezone") {
+		if err := updateUser(cs, d, d.Get("user_id").(string)); err != nil {
+			return fmt.Errorf("Error updating the user of account %s: %s", name, err)
+		}
+	}
+
+	return resourceCloudStackAccountRead(d, meta)
+}
+
+func resourceCloudStackAccountDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	// Create a new parameter struct
+	p := cs.Account.NewDeleteAccountParams(d.Id())
+
+	// Delete the account
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.Account.DeleteAccount(p)
+		return err
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting account %s: %s", d.Get("name").(string), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccCloudStackAccount_basic(t *testing.T) {
+	var account cloudstack.Account
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackAccountDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackAccount_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackAccountExists("cloudstack_account.foo", &account),
+					testAccCheckCloudStackAccountAttributes(&account),
+					resource.TestCheckResourceAttr(
+						"cloudstack_account.foo", "domain", "ROOT/tenants"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_account.foo", "username", "terraform-user"),
+					resource.TestCheckResourceAttrSet(
+						"cloudstack_account.foo", "user_id"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_account.foo", "state", "enabled"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackAccount_update(t *testing.T) {
+	var account cloudstack.Account
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackAccountDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackAccount_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackAccountExists("cloudstack_account.foo", &account),
+					testAccCheckCloudStackAccountAttributes(&account),
+				),
+			},
+
+			{
+				Config: testAccCloudStackAccount_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackAccountExists("cloudstack_account.foo", &account),
+					resource.TestCheckResourceAttr(
+						"cloudstack_account.foo", "name", "terraform-account-updated"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_account.foo", "email", "updated@example.com"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_account.foo", "last_name", "Updated"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackAccount_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackAccountDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackAccount_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_account.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"domain", "password"},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackAccountExists(
+	n string, account *cloudstack.Account) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No account ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*providerMeta)
+		a, _, err := cs.Account.GetAccountByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if a.Id != rs.Primary.ID {
+			return fmt.Errorf("Account not found")
+		}
+
+		*account = *a
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackAccountAttributes(
+	account *cloudstack.Account) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if account.Name != "terraform-account" {
+			return fmt.Errorf("Bad name: %s", account.Name)
+		}
+
+		if account.Domain != "tenants" {
+			return fmt.Errorf("Bad domain: %s", account.Domain)
+		}
+
+		if len(account.User) != 1 || account.User[0].Username != "terraform-user" {
+			return fmt.Errorf("Bad users: %+v", account.User)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackAccountDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*providerMeta)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_account" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No account ID is set")
+		}
+
+		_, _, err := cs.Account.GetAccountByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Account %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackAccount_basic = `
+resource "cloudstack_account" "foo" {
+  name       = "terraform-account"
+  domain     = "ROOT/tenants"
+  username   = "terraform-user"
+  password   = "s3cr3t"
+  email      = "terraform@example.com"
+  first_name = "Terraform"
+  last_name  = "User"
+}`
+
+const testAccCloudStackAccount_update = `
+resource "cloudstack_account" "foo" {
+  name       = "terraform-account-updated"
+  domain     = "ROOT/tenants"
+  username   = "terraform-user"
+  password   = "n3w-s3cr3t"
+  email      = "updated@example.com"
+  first_name = "Terraform"
+  last_name  = "Updated"
+}`
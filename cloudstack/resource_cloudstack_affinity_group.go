@@ -22,9 +22,9 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
@@ -52,14 +52,30 @@ func resourceCloudStackAffinityGroup() *schema.Resource {
 			},
 
 			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(affinityGroupTypes, false),
+			},
+
+			"project": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 				ForceNew: true,
 			},
 
-			"project": {
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
 		},
@@ -67,7 +83,7 @@ func resourceCloudStackAffinityGroup() *schema.Resource {
 }
 
 func resourceCloudStackAffinityGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	name := d.Get("name").(string)
 	affinityGroupType := d.Get("type").(string)
@@ -87,6 +103,11 @@ func resourceCloudStackAffinityGroupCreate(d *schema.ResourceData, meta interfac
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	log.Printf("[DEBUG] Creating affinity group %s", name)
 	r, err := cs.AffinityGroup.CreateAffinityGroup(p)
 	if err != nil {
@@ -100,7 +121,7 @@ func resourceCloudStackAffinityGroupCreate(d *schema.ResourceData, meta interfac
 }
 
 func resourceCloudStackAffinityGroupRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	log.Printf("[DEBUG] Rerieving affinity group %s", d.Get("name").(string))
 
@@ -108,9 +129,10 @@ func resourceCloudStackAffinityGroupRead(d *schema.ResourceData, meta interface{
 	ag, count, err := cs.AffinityGroup.GetAffinityGroupByID(
 		d.Id(),
 		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] Affinity group %s does not longer exist", d.Get("name").(string))
 			d.SetId("")
 			return nil
@@ -124,11 +146,13 @@ func resourceCloudStackAffinityGroupRead(d *schema.ResourceData, meta interface{
 	d.Set("description", ag.Description)
 	d.Set("type", ag.Type)
 
+	setAccountAndDomain(d, ag.Account, ag.Domain, ag.Domainid)
+
 	return nil
 }
 
 func resourceCloudStackAffinityGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.AffinityGroup.NewDeleteAffinityGroupParams()
@@ -139,13 +163,18 @@ func resourceCloudStackAffinityGroupDelete(d *schema.ResourceData, meta interfac
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// Delete the affinity group
-	_, err := cs.AffinityGroup.DeleteAffinityGroup(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.AffinityGroup.DeleteAffinityGroup(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
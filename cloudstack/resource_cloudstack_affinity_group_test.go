@@ -78,7 +78,7 @@ func testAccCheckCloudStackAffinityGroupExists(
 			return fmt.Errorf("No affinity group ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		ag, _, err := cs.AffinityGroup.GetAffinityGroupByID(rs.Primary.ID)
 
 		if err != nil {
@@ -116,7 +116,7 @@ func testAccCheckCloudStackAffinityGroupAttributes(
 }
 
 func testAccCheckCloudStackAffinityGroupDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_affinity_group" {
@@ -23,7 +23,6 @@ import (
 	"fmt"
 	"log"
 	"net/url"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -36,6 +35,9 @@ func resourceCloudStackAutoScaleVMProfile() *schema.Resource {
 		Read:   resourceCloudStackAutoScaleVMProfileRead,
 		Update: resourceCloudStackAutoScaleVMProfileUpdate,
 		Delete: resourceCloudStackAutoScaleVMProfileDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"service_offering": {
@@ -56,9 +58,10 @@ func resourceCloudStackAutoScaleVMProfile() *schema.Resource {
 			},
 
 			"destroy_vm_grace_period": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateDuration,
 			},
 
 			"other_deploy_params": {
@@ -74,7 +77,7 @@ func resourceCloudStackAutoScaleVMProfile() *schema.Resource {
 }
 
 func resourceCloudStackAutoScaleVMProfileCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Retrieve the service_offering ID
 	serviceofferingid, e := retrieveID(cs, "service_offering", d.Get("service_offering").(string))
@@ -130,12 +133,12 @@ func resourceCloudStackAutoScaleVMProfileCreate(d *schema.ResourceData, meta int
 }
 
 func resourceCloudStackAutoScaleVMProfileRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	p, count, err := cs.AutoScale.GetAutoScaleVmProfileByID(d.Id())
 
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf(
 				"[DEBUG] AutoScaleVmProfile %s no longer exists", d.Id())
 			d.SetId("")
@@ -189,7 +192,7 @@ func resourceCloudStackAutoScaleVMProfileRead(d *schema.ResourceData, meta inter
 }
 
 func resourceCloudStackAutoScaleVMProfileUpdate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.AutoScale.NewUpdateAutoScaleVmProfileParams(d.Id())
@@ -229,19 +232,19 @@ func resourceCloudStackAutoScaleVMProfileUpdate(d *schema.ResourceData, meta int
 }
 
 func resourceCloudStackAutoScaleVMProfileDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.AutoScale.NewDeleteAutoScaleVmProfileParams(d.Id())
 
 	// Delete the template
 	log.Printf("[INFO] Deleting AutoScaleVmProfile: %s", d.Id())
-	_, err := cs.AutoScale.DeleteAutoScaleVmProfile(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.AutoScale.DeleteAutoScaleVmProfile(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
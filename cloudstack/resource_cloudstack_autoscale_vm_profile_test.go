@@ -80,9 +80,29 @@ func TestAccCloudStackAutoscaleVMProfile_update(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackAutoscaleVMProfile_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackAutoscaleVMProfileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackAutoscaleVMProfile_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_autoscale_vm_profile.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"metadata"},
+			},
+		},
+	})
+}
+
 func testAccCheckResourceMetadata(vmProfile *cloudstack.AutoScaleVmProfile) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		p := cs.Resourcemetadata.NewListResourceDetailsParams("AutoScaleVmProfile")
 		p.SetResourceid(vmProfile.Id)
 		response, err := cs.Resourcemetadata.ListResourceDetails(p)
@@ -109,7 +129,7 @@ func testAccCheckCloudStackAutoscaleVMProfileExists(
 			return fmt.Errorf("No vmProfile ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		avp, _, err := cs.AutoScale.GetAutoScaleVmProfileByID(rs.Primary.ID)
 
 		if err != nil {
@@ -129,7 +149,7 @@ func testAccCheckCloudStackAutoscaleVMProfileExists(
 func testAccCheckCloudStackAutoscaleVMProfileBasicAttributes(
 	vmProfile *cloudstack.AutoScaleVmProfile) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 
 		serviceofferingid, e := retrieveID(cs, "service_offering", "Small Instance")
 		if e != nil {
@@ -179,7 +199,7 @@ func testAccCheckCloudStackAutoscaleVMProfileUpdatedAttributes(
 }
 
 func testAccCheckCloudStackAutoscaleVMProfileDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_autoscale_vm_profile" {
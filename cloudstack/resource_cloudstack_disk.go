@@ -21,9 +21,9 @@ package cloudstack
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
@@ -37,6 +37,14 @@ func resourceCloudStackDisk() *schema.Resource {
 			State: importStatePassthrough,
 		},
 
+		CustomizeDiff: validateReferences("disk_offering", "project", "zone"),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: providerTimeout(),
+			Update: providerTimeout(),
+			Delete: providerTimeout(),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -51,9 +59,10 @@ func resourceCloudStackDisk() *schema.Resource {
 			},
 
 			"device_id": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Computed: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
 			},
 
 			"disk_offering": {
@@ -62,9 +71,10 @@ func resourceCloudStackDisk() *schema.Resource {
 			},
 
 			"size": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Computed: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
 			},
 
 			"shrink_ok": {
@@ -85,6 +95,21 @@ func resourceCloudStackDisk() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
 			"zone": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -97,7 +122,7 @@ func resourceCloudStackDisk() *schema.Resource {
 }
 
 func resourceCloudStackDiskCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutCreate))
 	d.Partial(true)
 
 	name := d.Get("name").(string)
@@ -106,8 +131,21 @@ func resourceCloudStackDiskCreate(d *schema.ResourceData, meta interface{}) erro
 	p := cs.Volume.NewCreateVolumeParams()
 	p.SetName(name)
 
+	// Retrieve the zone ID
+	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+	if e != nil {
+		return e.Error()
+	}
+	// Set the zone ID
+	p.SetZoneid(zoneid)
+
+	scope, err := lookupScope(cs, d, zoneid)
+	if err != nil {
+		return err
+	}
+
 	// Retrieve the disk_offering ID
-	diskofferingid, e := retrieveID(cs, "disk_offering", d.Get("disk_offering").(string))
+	diskofferingid, e := retrieveScopedID(cs, "disk_offering", d.Get("disk_offering").(string), scope)
 	if e != nil {
 		return e.Error()
 	}
@@ -124,13 +162,10 @@ func resourceCloudStackDiskCreate(d *schema.ResourceData, meta interface{}) erro
 		return err
 	}
 
-	// Retrieve the zone ID
-	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
-	if e != nil {
-		return e.Error()
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
 	}
-	// Set the zone ID
-	p.SetZoneid(zoneid)
 
 	// Create the new volume
 	r, err := cs.Volume.CreateVolume(p)
@@ -157,7 +192,7 @@ func resourceCloudStackDiskCreate(d *schema.ResourceData, meta interface{}) erro
 	d.SetPartial("tags")
 
 	if d.Get("attach").(bool) {
-		if err := resourceCloudStackDiskAttach(d, meta); err != nil {
+		if err := resourceCloudStackDiskAttach(d, cs); err != nil {
 			return fmt.Errorf("Error attaching the new disk %s to virtual machine: %s", name, err)
 		}
 
@@ -170,15 +205,16 @@ func resourceCloudStackDiskCreate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceCloudStackDiskRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the volume details
 	v, count, err := cs.Volume.GetVolumeByID(
 		d.Id(),
 		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			d.SetId("")
 			return nil
 		}
@@ -198,6 +234,7 @@ func resourceCloudStackDiskRead(d *schema.ResourceData, meta interface{}) error
 
 	setValueOrID(d, "disk_offering", v.Diskofferingname, v.Diskofferingid)
 	setValueOrID(d, "project", v.Project, v.Projectid)
+	setAccountAndDomain(d, v.Account, v.Domain, v.Domainid)
 	setValueOrID(d, "zone", v.Zonename, v.Zoneid)
 
 	if v.Virtualmachineid != "" {
@@ -209,22 +246,33 @@ func resourceCloudStackDiskRead(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceCloudStackDiskUpdate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutUpdate))
 	d.Partial(true)
 
 	name := d.Get("name").(string)
 
 	if d.HasChange("disk_offering") || d.HasChange("size") {
 		// Detach the volume (re-attach is done at the end of this function)
-		if err := resourceCloudStackDiskDetach(d, meta); err != nil {
+		if err := resourceCloudStackDiskDetach(d, cs); err != nil {
 			return fmt.Errorf("Error detaching disk %s from virtual machine: %s", name, err)
 		}
 
 		// Create a new parameter struct
 		p := cs.Volume.NewResizeVolumeParams(d.Id())
 
+		// Retrieve the zone ID
+		zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+		if e != nil {
+			return e.Error()
+		}
+
+		scope, err := lookupScope(cs, d, zoneid)
+		if err != nil {
+			return err
+		}
+
 		// Retrieve the disk_offering ID
-		diskofferingid, e := retrieveID(cs, "disk_offering", d.Get("disk_offering").(string))
+		diskofferingid, e := retrieveScopedID(cs, "disk_offering", d.Get("disk_offering").(string), scope)
 		if e != nil {
 			return e.Error()
 		}
@@ -256,14 +304,14 @@ func resourceCloudStackDiskUpdate(d *schema.ResourceData, meta interface{}) erro
 	// volume at the end of this function
 	if d.HasChange("device_id") || d.HasChange("virtual_machine") {
 		// Detach the volume
-		if err := resourceCloudStackDiskDetach(d, meta); err != nil {
+		if err := resourceCloudStackDiskDetach(d, cs); err != nil {
 			return fmt.Errorf("Error detaching disk %s from virtual machine: %s", name, err)
 		}
 	}
 
 	if d.Get("attach").(bool) {
 		// Attach the volume
-		err := resourceCloudStackDiskAttach(d, meta)
+		err := resourceCloudStackDiskAttach(d, cs)
 		if err != nil {
 			return fmt.Errorf("Error attaching disk %s to virtual machine: %s", name, err)
 		}
@@ -274,7 +322,7 @@ func resourceCloudStackDiskUpdate(d *schema.ResourceData, meta interface{}) erro
 		d.SetPartial("virtual_machine_id")
 	} else {
 		// Detach the volume
-		if err := resourceCloudStackDiskDetach(d, meta); err != nil {
+		if err := resourceCloudStackDiskDetach(d, cs); err != nil {
 			return fmt.Errorf("Error detaching disk %s from virtual machine: %s", name, err)
 		}
 	}
@@ -294,10 +342,10 @@ func resourceCloudStackDiskUpdate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceCloudStackDiskDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutDelete))
 
 	// Detach the volume
-	if err := resourceCloudStackDiskDetach(d, meta); err != nil {
+	if err := resourceCloudStackDiskDetach(d, cs); err != nil {
 		return err
 	}
 
@@ -305,11 +353,12 @@ func resourceCloudStackDiskDelete(d *schema.ResourceData, meta interface{}) erro
 	p := cs.Volume.NewDeleteVolumeParams(d.Id())
 
 	// Delete the voluem
-	if _, err := cs.Volume.DeleteVolume(p); err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.Volume.DeleteVolume(p)
+		return err
+	})
+	if err != nil {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -320,7 +369,7 @@ func resourceCloudStackDiskDelete(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceCloudStackDiskAttach(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	if virtualmachineid, ok := d.GetOk("virtual_machine_id"); ok {
 		// First check if the disk isn't already attached
@@ -348,7 +397,7 @@ func resourceCloudStackDiskAttach(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceCloudStackDiskDetach(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Check if the volume is actually attached, before detaching
 	if attached, err := isAttached(d, meta); err != nil || !attached {
@@ -392,12 +441,13 @@ func resourceCloudStackDiskDetach(d *schema.ResourceData, meta interface{}) erro
 }
 
 func isAttached(d *schema.ResourceData, meta interface{}) (bool, error) {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the volume details
 	v, _, err := cs.Volume.GetVolumeByID(
 		d.Id(),
 		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
 	)
 	if err != nil {
 		return false, err
@@ -407,7 +457,7 @@ func isAttached(d *schema.ResourceData, meta interface{}) (bool, error) {
 }
 
 func retryableAttachVolumeFunc(
-	cs *cloudstack.CloudStackClient,
+	cs *providerMeta,
 	p *cloudstack.AttachVolumeParams) func() (interface{}, error) {
 	return func() (interface{}, error) {
 		r, err := cs.Volume.AttachVolume(p)
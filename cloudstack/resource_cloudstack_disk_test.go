@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
@@ -122,6 +123,57 @@ func TestAccCloudStackDisk_import(t *testing.T) {
 	})
 }
 
+func TestResourceCloudStackDisk_resize(t *testing.T) {
+	s, cs := newSimulatorClient(t)
+	defer s.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceCloudStackDisk().Schema, map[string]interface{}{
+		"name":          "terraform-disk",
+		"disk_offering": "Custom",
+		"size":          10,
+		"zone":          "Sandbox-simulator",
+	})
+
+	if err := resourceCloudStackDiskCreate(d, cs); err != nil {
+		t.Fatalf("Error creating disk: %s", err)
+	}
+
+	if size := d.Get("size").(int); size != 10 {
+		t.Fatalf("Bad size: %d", size)
+	}
+
+	// Shrinking the disk should fail unless shrink_ok is set
+	state := d.State()
+	d = schema.TestResourceDataRaw(t, resourceCloudStackDisk().Schema, map[string]interface{}{
+		"name":          "terraform-disk",
+		"disk_offering": "Custom",
+		"size":          5,
+		"zone":          "Sandbox-simulator",
+	})
+	d.SetId(state.ID)
+
+	if err := resourceCloudStackDiskUpdate(d, cs); err == nil {
+		t.Fatal("Expected shrinking the disk to fail")
+	}
+
+	d.Set("shrink_ok", true)
+	if err := resourceCloudStackDiskUpdate(d, cs); err != nil {
+		t.Fatalf("Error resizing disk: %s", err)
+	}
+
+	if size := s.Object("volume", d.Id())["size"].(float64); size != 5<<30 {
+		t.Fatalf("Bad size: %.0f", size)
+	}
+
+	if err := resourceCloudStackDiskDelete(d, cs); err != nil {
+		t.Fatalf("Error deleting disk: %s", err)
+	}
+
+	if s.Object("volume", d.Id()) != nil {
+		t.Fatal("Expected the disk to be deleted")
+	}
+}
+
 func testAccCheckCloudStackDiskExists(
 	n string, disk *cloudstack.Volume) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
@@ -134,7 +186,7 @@ func testAccCheckCloudStackDiskExists(
 			return fmt.Errorf("No disk ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		volume, _, err := cs.Volume.GetVolumeByID(rs.Primary.ID)
 
 		if err != nil {
@@ -180,7 +232,7 @@ func testAccCheckCloudStackDiskResized(
 }
 
 func testAccCheckCloudStackDiskDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_disk" {
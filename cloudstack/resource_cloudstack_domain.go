@@ -0,0 +1,189 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceCloudStackDomain() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackDomainCreate,
+		Read:   resourceCloudStackDomainRead,
+		Update: resourceCloudStackDomainUpdate,
+		Delete: resourceCloudStackDomainDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"parent_domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"network_domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"cleanup": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"path": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackDomainCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+	defer invalidateIDs(cs, "domain")
+
+	name := d.Get("name").(string)
+
+	// The typed response of the client does not match the API, so the domain
+	// is created using a custom request
+	p := &cloudstack.CustomServiceParams{}
+	p.SetParam("name", name)
+
+	// If there is a parent domain supplied, we retrieve and set the parent domain id
+	if parent, ok := d.GetOk("parent_domain"); ok {
+		parentid, e := retrieveID(cs, "domain", parent.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetParam("parentdomainid", parentid)
+	}
+
+	// If there is a network domain supplied, make sure to add it to the request
+	if networkDomain, ok := d.GetOk("network_domain"); ok {
+		p.SetParam("networkdomain", networkDomain.(string))
+	}
+
+	var r struct {
+		Domain cloudstack.Domain `json:"domain"`
+	}
+
+	log.Printf("[DEBUG] Creating domain %s", name)
+	if err := cs.Custom.CustomRequest("createDomain", p, &r); err != nil {
+		return fmt.Errorf("Error creating domain %s: %s", name, err)
+	}
+
+	d.SetId(r.Domain.Id)
+
+	return resourceCloudStackDomainRead(d, meta)
+}
+
+func resourceCloudStackDomainRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	// Get the domain details
+	domain, count, err := cs.Domain.GetDomainByID(d.Id())
+	if err != nil {
+		if count == 0 || isNotFound(err) {
+			log.Printf("[DEBUG] Domain %s does no longer exist", d.Get("name").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("name", domain.Name)
+	d.Set("network_domain", domain.Networkdomain)
+	d.Set("path", domain.Path)
+
+	setDomain(d, "parent_domain", domain.Parentdomainname, domain.Parentdomainid)
+
+	return nil
+}
+
+func resourceCloudStackDomainUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+	defer invalidateIDs(cs, "domain")
+
+	name := d.Get("name").(string)
+
+	// Check if the name or network domain is changed
+	if d.HasChange("name") || d.HasChange("network_domain") {
+		// Create a new parameter struct
+		p := cs.Domain.NewUpdateDomainParams(d.Id())
+
+		if d.HasChange("name") {
+			p.SetName(name)
+		}
+
+		if d.HasChange("network_domain") {
+			p.SetNetworkdomain(d.Get("network_domain").(string))
+		}
+
+		// Update the domain
+		_, err := cs.Domain.UpdateDomain(p)
+		if err != nil {
+			return fmt.Errorf("Error updating domain %s: %s", name, err)
+		}
+	}
+
+	return resourceCloudStackDomainRead(d, meta)
+}
+
+func resourceCloudStackDomainDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+	defer invalidateIDs(cs, "domain")
+
+	// Create a new parameter struct
+	p := cs.Domain.NewDeleteDomainParams(d.Id())
+
+	// Delete all accounts and resources of the domain if requested
+	p.SetCleanup(d.Get("cleanup").(bool))
+
+	// Delete the domain
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.Domain.DeleteDomain(p)
+		return err
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting domain %s: %s", d.Get("name").(string), err)
+	}
+
+	return nil
+}
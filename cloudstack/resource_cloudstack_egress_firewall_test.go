@@ -26,7 +26,6 @@ import (
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 func TestAccCloudStackEgressFirewall_basic(t *testing.T) {
@@ -53,6 +52,56 @@ func TestAccCloudStackEgressFirewall_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackEgressFirewall_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackEgressFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackEgressFirewall_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_egress_firewall.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"managed", "parallelism"},
+			},
+		},
+	})
+}
+
+func TestAccCloudStackEgressFirewall_importProject(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackEgressFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackEgressFirewall_project,
+			},
+
+			{
+				ResourceName:            "cloudstack_egress_firewall.foo",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccCloudStackEgressFirewallProjectImportStateID,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"managed", "parallelism"},
+			},
+		},
+	})
+}
+
+func testAccCloudStackEgressFirewallProjectImportStateID(s *terraform.State) (string, error) {
+	rs, ok := s.RootModule().Resources["cloudstack_egress_firewall.foo"]
+	if !ok {
+		return "", fmt.Errorf("Not found: cloudstack_egress_firewall.foo")
+	}
+
+	return fmt.Sprintf("terraform/%s", rs.Primary.ID), nil
+}
+
 func TestAccCloudStackEgressFirewall_update(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -116,7 +165,7 @@ func testAccCheckCloudStackEgressFirewallRulesExist(n string) resource.TestCheck
 				continue
 			}
 
-			cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+			cs := testAccProvider.Meta().(*providerMeta)
 			_, count, err := cs.Firewall.GetEgressFirewallRuleByID(id)
 
 			if err != nil {
@@ -133,7 +182,7 @@ func testAccCheckCloudStackEgressFirewallRulesExist(n string) resource.TestCheck
 }
 
 func testAccCheckCloudStackEgressFirewallDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_egress_firewall" {
@@ -200,3 +249,23 @@ resource "cloudstack_egress_firewall" "foo" {
     ports = ["80", "1000-2000"]
   }
 }`
+
+const testAccCloudStackEgressFirewall_project = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  project = "terraform"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_egress_firewall" "foo" {
+  network_id = "${cloudstack_network.foo.id}"
+  project = "terraform"
+
+  rule {
+    cidr_list = ["10.1.1.10/32"]
+    protocol = "tcp"
+    ports = ["80", "1000-2000"]
+  }
+}`
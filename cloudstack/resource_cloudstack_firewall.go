@@ -24,10 +24,10 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
@@ -37,6 +37,9 @@ func resourceCloudStackFirewall() *schema.Resource {
 		Read:   resourceCloudStackFirewallRead,
 		Update: resourceCloudStackFirewallUpdate,
 		Delete: resourceCloudStackFirewallDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackFirewallImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"ip_address_id": {
@@ -45,6 +48,12 @@ func resourceCloudStackFirewall() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
 			"managed": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -59,32 +68,41 @@ func resourceCloudStackFirewall() *schema.Resource {
 						"cidr_list": {
 							Type:     schema.TypeSet,
 							Required: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-							Set:      schema.HashString,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateCIDR,
+							},
+							Set: schema.HashString,
 						},
 
 						"protocol": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"tcp", "udp", "icmp"}, false),
 						},
 
 						"icmp_type": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateICMP,
 						},
 
 						"icmp_code": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateICMP,
 						},
 
 						"ports": {
 							Type:     schema.TypeSet,
 							Optional: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-							Set:      schema.HashString,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validatePortRange,
+							},
+							Set: schema.HashString,
 						},
 
 						"uuids": {
@@ -96,9 +114,10 @@ func resourceCloudStackFirewall() *schema.Resource {
 			},
 
 			"parallelism": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  2,
+				Type:       schema.TypeInt,
+				Optional:   true,
+				Default:    2,
+				Deprecated: "API calls are now limited by the provider wide 'max_requests_per_second' and 'max_concurrent_requests' arguments",
 			},
 		},
 	}
@@ -136,28 +155,26 @@ func createFirewallRules(d *schema.ResourceData, meta interface{}, rules *schema
 	var wg sync.WaitGroup
 	wg.Add(nrs.Len())
 
-	sem := make(chan struct{}, d.Get("parallelism").(int))
+	var mu sync.Mutex
 	for _, rule := range nrs.List() {
-		// Put in a tiny sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Create a single rule
 			err := createFirewallRule(d, meta, rule)
 
 			// If we have at least one UUID, we need to save the rule
 			if len(rule["uuids"].(map[string]interface{})) > 0 {
+				mu.Lock()
 				rules.Add(rule)
+				mu.Unlock()
 			}
 
 			if err != nil {
+				mu.Lock()
 				errs = multierror.Append(errs, err)
+				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -167,7 +184,7 @@ func createFirewallRules(d *schema.ResourceData, meta interface{}, rules *schema
 }
 
 func createFirewallRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 	uuids := rule["uuids"].(map[string]interface{})
 
 	// Make sure all required rule parameters are there
@@ -214,6 +231,10 @@ func createFirewallRule(d *schema.ResourceData, meta interface{}, rule map[strin
 				}
 
 				m := splitPorts.FindStringSubmatch(port.(string))
+				if m == nil {
+					return fmt.Errorf(
+						"%q is not a valid port value. Valid options are '80' or '80-90'", port.(string))
+				}
 
 				startPort, err := strconv.Atoi(m[1])
 				if err != nil {
@@ -249,13 +270,17 @@ func createFirewallRule(d *schema.ResourceData, meta interface{}, rule map[strin
 }
 
 func resourceCloudStackFirewallRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get all the rules from the running environment
 	p := cs.Firewall.NewListFirewallRulesParams()
 	p.SetIpaddressid(d.Id())
 	p.SetListall(true)
 
+	if err := setProjectid(p, cs, d); err != nil {
+		return err
+	}
+
 	l, err := cs.Firewall.ListFirewallRules(p)
 	if err != nil {
 		return err
@@ -448,34 +473,90 @@ func resourceCloudStackFirewallDelete(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+func resourceCloudStackFirewallImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*providerMeta)
+
+	// Split off the optional project
+	if _, err := importStatePassthrough(d, meta); err != nil {
+		return nil, err
+	}
+
+	// Make sure the IP address exists
+	_, _, err := cs.Address.GetPublicIpAddressByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving IP address %s: %s", d.Id(), err)
+	}
+
+	// Get all the rules from the running environment
+	p := cs.Firewall.NewListFirewallRulesParams()
+	p.SetIpaddressid(d.Id())
+	p.SetListall(true)
+
+	if err := setProjectid(p, cs, d); err != nil {
+		return nil, err
+	}
+
+	l, err := cs.Firewall.ListFirewallRules(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []ruleSetRule
+	for _, r := range l.FirewallRules {
+		rs = append(rs, ruleSetRule{
+			id:        r.Id,
+			protocol:  r.Protocol,
+			cidrs:     splitList(r.Cidrlist),
+			startPort: r.Startport,
+			endPort:   r.Endport,
+			icmpType:  r.Icmptype,
+			icmpCode:  r.Icmpcode,
+		})
+	}
+
+	// Group the rules back into the rules they were created from
+	rules := resourceCloudStackFirewall().Schema["rule"].ZeroValue().(*schema.Set)
+	for _, rule := range groupRules(rs) {
+		rules.Add(rule)
+	}
+
+	// An imported firewall manages all rules of the IP address
+	d.Set("ip_address_id", d.Id())
+	d.Set("managed", true)
+	d.Set("rule", rules)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func deleteFirewallRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, ors *schema.Set) error {
 	var errs *multierror.Error
 
 	var wg sync.WaitGroup
 	wg.Add(ors.Len())
 
-	sem := make(chan struct{}, d.Get("parallelism").(int))
+	var mu sync.Mutex
 	for _, rule := range ors.List() {
-		// Put a sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Delete a single rule
 			err := deleteFirewallRule(d, meta, rule)
 
 			// If we have at least one UUID, we need to save the rule
 			if len(rule["uuids"].(map[string]interface{})) > 0 {
+				mu.Lock()
 				rules.Add(rule)
+				mu.Unlock()
 			}
 
 			if err != nil {
+				mu.Lock()
 				errs = multierror.Append(errs, err)
+				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -485,7 +566,7 @@ func deleteFirewallRules(d *schema.ResourceData, meta interface{}, rules *schema
 }
 
 func deleteFirewallRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 	uuids := rule["uuids"].(map[string]interface{})
 
 	for k, id := range uuids {
@@ -500,10 +581,7 @@ func deleteFirewallRule(d *schema.ResourceData, meta interface{}, rule map[strin
 		// Delete the rule
 		if _, err := cs.Firewall.DeleteFirewallRule(p); err != nil {
 
-			// This is a very poor way to be told the ID does no longer exist :(
-			if strings.Contains(err.Error(), fmt.Sprintf(
-				"Invalid parameter id value=%s due to incorrect long value format, "+
-					"or entity does not exist", id.(string))) {
+			if isNotFound(err) {
 				delete(uuids, k)
 				continue
 			}
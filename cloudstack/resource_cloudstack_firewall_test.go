@@ -21,12 +21,12 @@ package cloudstack
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 func TestAccCloudStackFirewall_basic(t *testing.T) {
@@ -61,6 +61,56 @@ func TestAccCloudStackFirewall_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackFirewall_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackFirewall_update,
+			},
+
+			{
+				ResourceName:            "cloudstack_firewall.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"managed", "parallelism"},
+			},
+		},
+	})
+}
+
+func TestAccCloudStackFirewall_importProject(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackFirewall_project,
+			},
+
+			{
+				ResourceName:            "cloudstack_firewall.foo",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccCloudStackFirewallProjectImportStateID,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"managed", "parallelism"},
+			},
+		},
+	})
+}
+
+func testAccCloudStackFirewallProjectImportStateID(s *terraform.State) (string, error) {
+	rs, ok := s.RootModule().Resources["cloudstack_firewall.foo"]
+	if !ok {
+		return "", fmt.Errorf("Not found: cloudstack_firewall.foo")
+	}
+
+	return fmt.Sprintf("terraform/%s", rs.Primary.ID), nil
+}
+
 func TestAccCloudStackFirewall_update(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -126,6 +176,30 @@ func TestAccCloudStackFirewall_update(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackFirewall_invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCloudStackFirewall_invalidPorts,
+				ExpectError: regexp.MustCompile("must be a port or a port range"),
+			},
+
+			{
+				Config:      testAccCloudStackFirewall_invalidCIDR,
+				ExpectError: regexp.MustCompile("must be a valid CIDR"),
+			},
+
+			{
+				Config:      testAccCloudStackFirewall_invalidProtocol,
+				ExpectError: regexp.MustCompile("expected rule.0.protocol to be one of"),
+			},
+		},
+	})
+}
+
 func testAccCheckCloudStackFirewallRulesExist(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -142,7 +216,7 @@ func testAccCheckCloudStackFirewallRulesExist(n string) resource.TestCheckFunc {
 				continue
 			}
 
-			cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+			cs := testAccProvider.Meta().(*providerMeta)
 			_, count, err := cs.Firewall.GetFirewallRuleByID(id)
 
 			if err != nil {
@@ -159,7 +233,7 @@ func testAccCheckCloudStackFirewallRulesExist(n string) resource.TestCheckFunc {
 }
 
 func testAccCheckCloudStackFirewallDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_firewall" {
@@ -240,3 +314,57 @@ resource "cloudstack_firewall" "foo" {
     ports = ["80", "443"]
   }
 }`
+
+const testAccCloudStackFirewall_invalidPorts = `
+resource "cloudstack_firewall" "foo" {
+  ip_address_id = "c2a9b5a4-2b3f-4bd5-9b9b-1b1b0d6c7c6e"
+
+  rule {
+    cidr_list = ["10.0.0.0/24"]
+    protocol = "tcp"
+    ports = ["80-"]
+  }
+}`
+
+const testAccCloudStackFirewall_invalidCIDR = `
+resource "cloudstack_firewall" "foo" {
+  ip_address_id = "c2a9b5a4-2b3f-4bd5-9b9b-1b1b0d6c7c6e"
+
+  rule {
+    cidr_list = ["10.0.0.0/33"]
+    protocol = "tcp"
+    ports = ["80"]
+  }
+}`
+
+const testAccCloudStackFirewall_invalidProtocol = `
+resource "cloudstack_firewall" "foo" {
+  ip_address_id = "c2a9b5a4-2b3f-4bd5-9b9b-1b1b0d6c7c6e"
+
+  rule {
+    cidr_list = ["10.0.0.0/24"]
+    protocol = "tcpp"
+    ports = ["80"]
+  }
+}`
+
+const testAccCloudStackFirewall_project = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  source_nat_ip = true
+  project = "terraform"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_firewall" "foo" {
+  ip_address_id = "${cloudstack_network.foo.source_nat_ip_id}"
+  project = "terraform"
+
+  rule {
+    cidr_list = ["10.0.0.0/24"]
+    protocol = "tcp"
+    ports = ["80", "1000-2000"]
+  }
+}`
@@ -23,11 +23,17 @@ import (
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform/helper/customdiff"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
@@ -41,6 +47,30 @@ func resourceCloudStackInstance() *schema.Resource {
 			State: resourceCloudStackInstanceImport,
 		},
 
+		CustomizeDiff: customdiff.Sequence(
+			// The root disk can be grown in place, but it can't be shrunk
+			customdiff.ForceNewIf("root_disk_size", func(d *schema.ResourceDiff, meta interface{}) bool {
+				o, n := d.GetChange("root_disk_size")
+				return d.NewValueKnown("root_disk_size") && n.(int) < o.(int)
+			}),
+			// A new template requires a new instance, unless it may be rebuilt
+			customdiff.ForceNewIf("template", func(d *schema.ResourceDiff, meta interface{}) bool {
+				return d.HasChange("template") && !d.Get("rebuild_on_template_change").(bool)
+			}),
+			// A new placement requires a new instance, unless it may be migrated
+			forceNewOnPlacementChange("host_id"),
+			forceNewOnPlacementChange("cluster_id"),
+			forceNewOnPlacementChange("pod_id"),
+			validateMigration,
+			resourceCloudStackInstanceCustomizeDiff,
+		),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: providerTimeout(),
+			Update: providerTimeout(),
+			Delete: providerTimeout(),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -59,31 +89,108 @@ func resourceCloudStackInstance() *schema.Resource {
 				Required: true,
 			},
 
-			"network_id": {
-				Type:     schema.TypeString,
+			"cpu_number": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"cpu_speed": {
+				Type:     schema.TypeInt,
 				Optional: true,
 				Computed: true,
+			},
+
+			"memory": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"details": {
+				Type:     schema.TypeMap,
+				Optional: true,
 				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 
-			"ip_address": {
+			"network_id": {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
 				ForceNew: true,
 			},
 
+			"ip_address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPAddress,
+			},
+
+			"network": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"network_id", "ip_address"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"network_id": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+
+						"ip_address": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ForceNew:     true,
+							ValidateFunc: validateIPAddress,
+						},
+
+						"ipv6_address": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ForceNew:     true,
+							ValidateFunc: validateIPAddress,
+						},
+
+						"mac_address": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+
+						"default": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+
 			"template": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
+			},
+
+			"rebuild_on_template_change": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
 			},
 
 			"root_disk_size": {
 				Type:     schema.TypeInt,
 				Optional: true,
 				Computed: true,
-				ForceNew: true,
 			},
 
 			"group": {
@@ -133,6 +240,21 @@ func resourceCloudStackInstance() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
 			"zone": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -144,6 +266,39 @@ func resourceCloudStackInstance() *schema.Resource {
 				Optional: true,
 			},
 
+			"host_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"cluster_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"pod_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"deployment_planner": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"migrate_on_placement_change": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"hypervisor": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
 			"start_vm": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -151,6 +306,25 @@ func resourceCloudStackInstance() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"Running", "Stopped"}, false),
+			},
+
+			"force_stop": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"reboot_trigger": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
 			"user_data": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -177,20 +351,45 @@ func resourceCloudStackInstance() *schema.Resource {
 }
 
 func resourceCloudStackInstanceCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutCreate))
 
-	// Retrieve the service_offering ID
-	serviceofferingid, e := retrieveID(cs, "service_offering", d.Get("service_offering").(string))
+	// Retrieve the zone ID
+	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
 	if e != nil {
 		return e.Error()
 	}
 
-	// Retrieve the zone ID
-	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+	scope, err := lookupScope(cs, d, zoneid)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve the service_offering ID
+	serviceofferingid, e := retrieveScopedID(cs, "service_offering", d.Get("service_offering").(string), scope)
 	if e != nil {
 		return e.Error()
 	}
 
+	// Retrieve the custom resources of the service offering
+	details, err := serviceOfferingDetails(cs, d, serviceofferingid)
+	if err != nil {
+		return err
+	}
+
+	if details == nil {
+		for _, k := range []string{"cpu_number", "cpu_speed", "memory"} {
+			if _, ok := d.GetOk(k); ok {
+				return fmt.Errorf(
+					"Error creating instance: %s can only be set for a custom service offering", k)
+			}
+		}
+		details = make(map[string]string)
+	}
+
+	for k, v := range d.Get("details").(map[string]interface{}) {
+		details[k] = v.(string)
+	}
+
 	// Retrieve the zone object
 	zone, _, err := cs.Zone.GetZoneByID(zoneid)
 	if err != nil {
@@ -198,82 +397,113 @@ func resourceCloudStackInstanceCreate(d *schema.ResourceData, meta interface{})
 	}
 
 	// Retrieve the template ID
-	templateid, e := retrieveTemplateID(cs, zone.Id, d.Get("template").(string))
+	templateid, e := retrieveScopedID(cs, "template", d.Get("template").(string), scope)
 	if e != nil {
 		return e.Error()
 	}
 
+	networks := d.Get("network").([]interface{})
+	if err := verifyInstanceNetworks(networks); err != nil {
+		return err
+	}
+
 	// Create a new parameter struct
-	p := cs.VirtualMachine.NewDeployVirtualMachineParams(serviceofferingid, templateid, zone.Id)
-	p.SetStartvm(d.Get("start_vm").(bool))
+	p := &deployVirtualMachineParams{}
+	p.SetParam("serviceofferingid", serviceofferingid)
+	p.SetParam("templateid", templateid)
+	p.SetParam("zoneid", zone.Id)
+
+	// A configured state takes precedence over start_vm
+	startvm := d.Get("start_vm").(bool)
+	if state, ok := d.GetOk("state"); ok {
+		startvm = state.(string) == "Running"
+	}
+	p.SetParam("startvm", startvm)
+
+	// Set the placement of the virtual machine
+	for param, k := range map[string]string{
+		"hostid":            "host_id",
+		"clusterid":         "cluster_id",
+		"podid":             "pod_id",
+		"deploymentplanner": "deployment_planner",
+	} {
+		if v, ok := d.GetOk(k); ok {
+			p.SetParam(param, v.(string))
+		}
+	}
+
+	// Set the details, including the custom resources
+	for k, v := range details {
+		p.SetParam(fmt.Sprintf("details[0].%s", k), v)
+	}
 
 	// Set the name
 	name, hasName := d.GetOk("name")
 	if hasName {
-		p.SetName(name.(string))
+		p.SetParam("name", name.(string))
 	}
 
 	// Set the display name
 	if displayname, ok := d.GetOk("display_name"); ok {
-		p.SetDisplayname(displayname.(string))
+		p.SetParam("displayname", displayname.(string))
 	} else if hasName {
-		p.SetDisplayname(name.(string))
+		p.SetParam("displayname", name.(string))
 	}
 
 	// If there is a root_disk_size supplied, add it to the parameter struct
 	if rootdisksize, ok := d.GetOk("root_disk_size"); ok {
-		p.SetRootdisksize(int64(rootdisksize.(int)))
+		p.SetParam("rootdisksize", int64(rootdisksize.(int)))
 	}
 
-	if zone.Networktype == "Advanced" {
+	if len(networks) > 0 {
+		// Set the networks, in the order of the NICs to create
+		for i, n := range networks {
+			network := n.(map[string]interface{})
+			p.SetParam(fmt.Sprintf("iptonetworklist[%d].networkid", i), network["network_id"].(string))
+
+			if ipaddress := network["ip_address"].(string); ipaddress != "" {
+				p.SetParam(fmt.Sprintf("iptonetworklist[%d].ip", i), ipaddress)
+			}
+			if ip6address := network["ipv6_address"].(string); ip6address != "" {
+				p.SetParam(fmt.Sprintf("iptonetworklist[%d].ipv6", i), ip6address)
+			}
+			if macaddress := network["mac_address"].(string); macaddress != "" {
+				p.SetParam(fmt.Sprintf("iptonetworklist[%d].mac", i), macaddress)
+			}
+		}
+	} else if zone.Networktype == "Advanced" {
 		// Set the default network ID
-		p.SetNetworkids([]string{d.Get("network_id").(string)})
+		p.SetParam("networkids", d.Get("network_id").(string))
 	}
 
 	// If there is a ipaddres supplied, add it to the parameter struct
 	if ipaddress, ok := d.GetOk("ip_address"); ok {
-		p.SetIpaddress(ipaddress.(string))
+		p.SetParam("ipaddress", ipaddress.(string))
 	}
 
 	// If there is a group supplied, add it to the parameter struct
 	if group, ok := d.GetOk("group"); ok {
-		p.SetGroup(group.(string))
+		p.SetParam("group", group.(string))
 	}
 
 	// If there are affinity group IDs supplied, add them to the parameter struct
 	if agIDs := d.Get("affinity_group_ids").(*schema.Set); agIDs.Len() > 0 {
-		var groups []string
-		for _, group := range agIDs.List() {
-			groups = append(groups, group.(string))
-		}
-		p.SetAffinitygroupids(groups)
+		p.SetParam("affinitygroupids", joinSet(agIDs))
 	}
 
 	// If there are affinity group names supplied, add them to the parameter struct
 	if agNames := d.Get("affinity_group_names").(*schema.Set); agNames.Len() > 0 {
-		var groups []string
-		for _, group := range agNames.List() {
-			groups = append(groups, group.(string))
-		}
-		p.SetAffinitygroupnames(groups)
+		p.SetParam("affinitygroupnames", joinSet(agNames))
 	}
 
 	// If there are security group IDs supplied, add them to the parameter struct
 	if sgIDs := d.Get("security_group_ids").(*schema.Set); sgIDs.Len() > 0 {
-		var groups []string
-		for _, group := range sgIDs.List() {
-			groups = append(groups, group.(string))
-		}
-		p.SetSecuritygroupids(groups)
+		p.SetParam("securitygroupids", joinSet(sgIDs))
 	}
 
 	// If there are security group names supplied, add them to the parameter struct
 	if sgNames := d.Get("security_group_names").(*schema.Set); sgNames.Len() > 0 {
-		var groups []string
-		for _, group := range sgNames.List() {
-			groups = append(groups, group.(string))
-		}
-		p.SetSecuritygroupnames(groups)
+		p.SetParam("securitygroupnames", joinSet(sgNames))
 	}
 
 	// If there is a project supplied, we retrieve and set the project id
@@ -281,9 +511,14 @@ func resourceCloudStackInstanceCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// If a keypair is supplied, add it to the parameter struct
 	if keypair, ok := d.GetOk("keypair"); ok {
-		p.SetKeypair(keypair.(string))
+		p.SetParam("keypair", keypair.(string))
 	}
 
 	if userData, ok := d.GetOk("user_data"); ok {
@@ -291,17 +526,26 @@ func resourceCloudStackInstanceCreate(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return err
 		}
-		p.SetUserdata(ud)
+		p.SetParam("userdata", ud)
 	}
 
 	// Create the new instance
-	r, err := cs.VirtualMachine.DeployVirtualMachine(p)
+	r, err := deployVirtualMachine(cs, p, d.Timeout(schema.TimeoutCreate))
 	if err != nil {
 		return fmt.Errorf("Error creating the new instance %s: %s", name, err)
 	}
 
 	d.SetId(r.Id)
 
+	// The NIC in the first network is the default NIC, so update the default
+	// NIC if another network is marked as the default network
+	if i := defaultNetwork(networks); i > 0 {
+		networkid := networks[i].(map[string]interface{})["network_id"].(string)
+		if err := updateDefaultNic(cs, r, networkid); err != nil {
+			return fmt.Errorf("Error setting the default NIC of instance %s: %s", name, err)
+		}
+	}
+
 	// Set tags if necessary
 	if err = setTags(cs, d, "userVm"); err != nil {
 		return fmt.Errorf("Error setting tags on the new instance %s: %s", name, err)
@@ -317,15 +561,16 @@ func resourceCloudStackInstanceCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceCloudStackInstanceRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the virtual machine details
 	vm, count, err := cs.VirtualMachine.GetVirtualMachineByID(
 		d.Id(),
 		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] Instance %s does no longer exist", d.Get("name").(string))
 			d.SetId("")
 			return nil
@@ -338,6 +583,16 @@ func resourceCloudStackInstanceRead(d *schema.ResourceData, meta interface{}) er
 	d.Set("name", vm.Name)
 	d.Set("display_name", vm.Displayname)
 	d.Set("group", vm.Group)
+	d.Set("state", vm.State)
+	d.Set("cpu_number", vm.Cpunumber)
+	d.Set("cpu_speed", vm.Cpuspeed)
+	d.Set("memory", vm.Memory)
+	d.Set("hypervisor", vm.Hypervisor)
+
+	// A stopped virtual machine isn't running on any host
+	if vm.Hostid != "" {
+		d.Set("host_id", vm.Hostid)
+	}
 
 	// In some rare cases (when destroying a machine failes) it can happen that
 	// an instance does not have any attached NIC anymore.
@@ -346,22 +601,22 @@ func resourceCloudStackInstanceRead(d *schema.ResourceData, meta interface{}) er
 		d.Set("ip_address", vm.Nic[0].Ipaddress)
 	}
 
-	// Create a new param struct.
-	p := cs.Volume.NewListVolumesParams()
-	p.SetType("ROOT")
-	p.SetVirtualmachineid(d.Id())
+	// Set the NICs the instance was deployed with, in the order of their
+	// devices. NICs added later, like by a cloudstack_nic resource, are left
+	// out so they don't force a new instance.
+	d.Set("network", flattenInstanceNetworks(deployedNics(d, vm.Nic)))
 
 	// Get the root disk of the instance.
-	l, err := cs.Volume.ListVolumes(p)
+	root, err := getRootDisk(cs, d)
 	if err != nil {
 		return err
 	}
 
 	// If we found the root disk, then update its size.
-	if len(l.Volumes) != 1 {
+	if root == nil {
 		log.Printf("[DEBUG] Failed to find root disk of instance: %s", vm.Name)
 	} else {
-		d.Set("root_disk_size", l.Volumes[0].Size>>30) // B to GiB
+		d.Set("root_disk_size", root.Size>>30) // B to GiB
 	}
 
 	if _, ok := d.GetOk("affinity_group_ids"); ok {
@@ -405,17 +660,22 @@ func resourceCloudStackInstanceRead(d *schema.ResourceData, meta interface{}) er
 	setValueOrID(d, "service_offering", vm.Serviceofferingname, vm.Serviceofferingid)
 	setValueOrID(d, "template", vm.Templatename, vm.Templateid)
 	setValueOrID(d, "project", vm.Project, vm.Projectid)
+	setAccountAndDomain(d, vm.Account, vm.Domain, vm.Domainid)
 	setValueOrID(d, "zone", vm.Zonename, vm.Zoneid)
 
 	return nil
 }
 
 func resourceCloudStackInstanceUpdate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutUpdate))
 	d.Partial(true)
 
 	name := d.Get("name").(string)
 
+	// Keep track of whether the virtual machine was already stopped and
+	// started again, so the state and reboot trigger don't do it twice
+	restarted := false
+
 	// Check if the display name is changed and if so, update the virtual machine
 	if d.HasChange("display_name") {
 		log.Printf("[DEBUG] Display name changed for %s, starting update", name)
@@ -456,12 +716,110 @@ func resourceCloudStackInstanceUpdate(d *schema.ResourceData, meta interface{})
 		d.SetPartial("group")
 	}
 
+	// Check if the template has changed and if so, rebuild the virtual machine
+	if d.HasChange("template") {
+		log.Printf("[DEBUG] Template changed for %s, rebuilding the instance", name)
+
+		// Retrieve the zone ID
+		zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+		if e != nil {
+			return e.Error()
+		}
+
+		// Retrieve the template ID
+		templateid, e := retrieveTemplateID(cs, zoneid, d.Get("template").(string))
+		if e != nil {
+			return e.Error()
+		}
+
+		// Create a new parameter struct
+		p := cs.VirtualMachine.NewRestoreVirtualMachineParams(d.Id())
+		p.SetTemplateid(templateid)
+
+		// Replace the root disk with a new one created from the template
+		_, err := cs.VirtualMachine.RestoreVirtualMachine(p)
+		if err != nil {
+			return fmt.Errorf(
+				"Error rebuilding instance %s with template %s: %s", name, d.Get("template").(string), err)
+		}
+
+		d.SetPartial("template")
+	}
+
+	// Check if the root disk size has changed or if the root disk was replaced
+	// while rebuilding and if so, grow the root disk
+	if d.HasChange("root_disk_size") || d.HasChange("template") {
+		if err := growRootDisk(cs, d); err != nil {
+			return fmt.Errorf(
+				"Error resizing the root disk of instance %s: %s", name, err)
+		}
+
+		d.SetPartial("root_disk_size")
+	}
+
+	// Check if the service offering or its custom resources have changed
+	scale := d.HasChange("service_offering") || d.HasChange("cpu_number") ||
+		d.HasChange("cpu_speed") || d.HasChange("memory")
+
+	// A dynamically scalable virtual machine is scaled up while it keeps
+	// running, in all other cases the virtual machine is stopped first
+	if scale {
+		vm, _, err := cs.VirtualMachine.GetVirtualMachineByID(
+			d.Id(),
+			cloudstack.WithProject(d.Get("project").(string)),
+			withAccount(cs, d),
+		)
+		if err != nil {
+			return fmt.Errorf("Error retrieving instance %s: %s", name, err)
+		}
+
+		if vm.Isdynamicallyscalable && vm.State == "Running" {
+			serviceofferingid, details, err := instanceServiceOffering(cs, d)
+			if err != nil {
+				return err
+			}
+
+			shrink, err := shrinksInstance(cs, vm, serviceofferingid, details)
+			if err != nil {
+				return err
+			}
+
+			if shrink {
+				log.Printf("[DEBUG] Service offering changed for %s, the instance must be stopped to scale down", name)
+			} else {
+				log.Printf("[DEBUG] Service offering changed for %s, scaling the running instance", name)
+
+				// Create a new parameter struct
+				p := cs.VirtualMachine.NewScaleVirtualMachineParams(d.Id(), serviceofferingid)
+				if details != nil {
+					p.SetDetails(details)
+				}
+
+				// Scale the virtual machine, if CloudStack rejects the new
+				// offering the service offering is changed while stopped
+				_, err = cs.VirtualMachine.ScaleVirtualMachine(p)
+				switch {
+				case err == nil:
+					d.SetPartial("service_offering")
+					d.SetPartial("cpu_number")
+					d.SetPartial("cpu_speed")
+					d.SetPartial("memory")
+					scale = false
+				case isAPIError(err):
+					log.Printf("[DEBUG] Unable to scale the running instance %s: %s", name, err)
+				default:
+					return fmt.Errorf(
+						"Error scaling instance %s: %s", name, err)
+				}
+			}
+		}
+	}
+
 	// Attributes that require reboot to update
-	if d.HasChange("name") || d.HasChange("service_offering") || d.HasChange("affinity_group_ids") ||
+	if scale || d.HasChange("name") || d.HasChange("affinity_group_ids") ||
 		d.HasChange("affinity_group_names") || d.HasChange("keypair") || d.HasChange("user_data") {
 		// Before we can actually make these changes, the virtual machine must be stopped
-		_, err := cs.VirtualMachine.StopVirtualMachine(
-			cs.VirtualMachine.NewStopVirtualMachineParams(d.Id()))
+		err := stopInstance(cs, d)
 		if err != nil {
 			return fmt.Errorf(
 				"Error stopping instance %s before making changes: %s", name, err)
@@ -488,17 +846,19 @@ func resourceCloudStackInstanceUpdate(d *schema.ResourceData, meta interface{})
 		}
 
 		// Check if the service offering is changed and if so, update the offering
-		if d.HasChange("service_offering") {
+		if scale {
 			log.Printf("[DEBUG] Service offering changed for %s, starting update", name)
 
-			// Retrieve the service_offering ID
-			serviceofferingid, e := retrieveID(cs, "service_offering", d.Get("service_offering").(string))
-			if e != nil {
-				return e.Error()
+			serviceofferingid, details, err := instanceServiceOffering(cs, d)
+			if err != nil {
+				return err
 			}
 
 			// Create a new parameter struct
 			p := cs.VirtualMachine.NewChangeServiceForVirtualMachineParams(d.Id(), serviceofferingid)
+			if details != nil {
+				p.SetDetails(details)
+			}
 
 			// Change the service offering
 			_, err = cs.VirtualMachine.ChangeServiceForVirtualMachine(p)
@@ -507,6 +867,9 @@ func resourceCloudStackInstanceUpdate(d *schema.ResourceData, meta interface{})
 					"Error changing the service offering for instance %s: %s", name, err)
 			}
 			d.SetPartial("service_offering")
+			d.SetPartial("cpu_number")
+			d.SetPartial("cpu_speed")
+			d.SetPartial("memory")
 		}
 
 		// Check if the affinity group IDs have changed and if so, update the IDs
@@ -589,12 +952,74 @@ func resourceCloudStackInstanceUpdate(d *schema.ResourceData, meta interface{})
 			d.SetPartial("user_data")
 		}
 
-		// Start the virtual machine again
-		_, err = cs.VirtualMachine.StartVirtualMachine(
-			cs.VirtualMachine.NewStartVirtualMachineParams(d.Id()))
+		// Start the virtual machine again, unless it should stay stopped
+		if d.Get("state").(string) != "Stopped" {
+			_, err = cs.VirtualMachine.StartVirtualMachine(
+				cs.VirtualMachine.NewStartVirtualMachineParams(d.Id()))
+			if err != nil {
+				return fmt.Errorf(
+					"Error starting instance %s after making changes", name)
+			}
+		}
+		d.SetPartial("state")
+		restarted = true
+	}
+
+	// Check if the state has changed and if so, start or stop the virtual machine
+	if d.HasChange("state") && !restarted {
+		o, n := d.GetChange("state")
+		log.Printf("[DEBUG] State for %s changed from %s to %s, starting update", name, o, n)
+
+		switch n.(string) {
+		case "Running":
+			_, err := cs.VirtualMachine.StartVirtualMachine(
+				cs.VirtualMachine.NewStartVirtualMachineParams(d.Id()))
+			if err != nil {
+				return fmt.Errorf("Error starting instance %s: %s", name, err)
+			}
+		case "Stopped":
+			if err := stopInstance(cs, d); err != nil {
+				return fmt.Errorf("Error stopping instance %s: %s", name, err)
+			}
+		}
+		d.SetPartial("state")
+		restarted = true
+	}
+
+	// Check if the placement has changed and if so, migrate the virtual machine
+	if d.HasChange("host_id") || d.HasChange("cluster_id") || d.HasChange("pod_id") {
+		hostid, err := migrationHost(cs, d)
 		if err != nil {
-			return fmt.Errorf(
-				"Error starting instance %s after making changes", name)
+			return fmt.Errorf("Error finding a host to migrate instance %s to: %s", name, err)
+		}
+
+		if hostid != "" {
+			log.Printf("[DEBUG] Placement changed for %s, migrating to host %s", name, hostid)
+
+			// Create a new parameter struct
+			p := cs.VirtualMachine.NewMigrateVirtualMachineParams(d.Id())
+			p.SetHostid(hostid)
+
+			// Migrate the virtual machine
+			_, err := cs.VirtualMachine.MigrateVirtualMachine(p)
+			if err != nil {
+				return fmt.Errorf("Error migrating instance %s: %s", name, err)
+			}
+		}
+
+		d.SetPartial("host_id")
+		d.SetPartial("cluster_id")
+		d.SetPartial("pod_id")
+	}
+
+	// Check if the reboot trigger has changed and if so, reboot the virtual machine
+	if d.HasChange("reboot_trigger") && !restarted && d.Get("state").(string) != "Stopped" {
+		log.Printf("[DEBUG] Reboot trigger changed for %s, rebooting", name)
+
+		_, err := cs.VirtualMachine.RebootVirtualMachine(
+			cs.VirtualMachine.NewRebootVirtualMachineParams(d.Id()))
+		if err != nil {
+			return fmt.Errorf("Error rebooting instance %s: %s", name, err)
 		}
 	}
 
@@ -612,7 +1037,7 @@ func resourceCloudStackInstanceUpdate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceCloudStackInstanceDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutDelete))
 
 	// Create a new parameter struct
 	p := cs.VirtualMachine.NewDestroyVirtualMachineParams(d.Id())
@@ -622,11 +1047,12 @@ func resourceCloudStackInstanceDelete(d *schema.ResourceData, meta interface{})
 	}
 
 	log.Printf("[INFO] Destroying instance: %s", d.Get("name").(string))
-	if _, err := cs.VirtualMachine.DestroyVirtualMachine(p); err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.VirtualMachine.DestroyVirtualMachine(p)
+		return err
+	})
+	if err != nil {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -639,9 +1065,378 @@ func resourceCloudStackInstanceImport(d *schema.ResourceData, meta interface{})
 	// We set start_vm to true as that matches the default and we assume that
 	// when you need to import an instance it means it is already running.
 	d.Set("start_vm", true)
+	d.Set("force_stop", false)
+	d.Set("rebuild_on_template_change", false)
+	d.Set("migrate_on_placement_change", false)
 	return importStatePassthrough(d, meta)
 }
 
+// deployVirtualMachineParams holds the parameters of a deployVirtualMachine
+// request. The client sends the iptonetworklist parameter as a map of keys and
+// values, while CloudStack expects a list of networks with their addresses, so
+// instances are deployed using a custom request.
+type deployVirtualMachineParams struct {
+	cloudstack.CustomServiceParams
+}
+
+func (p *deployVirtualMachineParams) SetProjectid(v string) {
+	p.SetParam("projectid", v)
+}
+
+func (p *deployVirtualMachineParams) SetAccount(v string) {
+	p.SetParam("account", v)
+}
+
+func (p *deployVirtualMachineParams) SetDomainid(v string) {
+	p.SetParam("domainid", v)
+}
+
+// deployVirtualMachine deploys a new virtual machine and waits for the async
+// job to finish. If the timeout is zero, the timeout of the provider is used.
+func deployVirtualMachine(cs *providerMeta, p *deployVirtualMachineParams, timeout time.Duration) (*cloudstack.VirtualMachine, error) {
+	var job struct {
+		JobID string `json:"jobid"`
+	}
+	if err := cs.Custom.CustomRequest("deployVirtualMachine", &p.CustomServiceParams, &job); err != nil {
+		return nil, err
+	}
+
+	if timeout <= 0 {
+		timeout = time.Duration(cs.config.Timeout) * time.Second
+	}
+
+	b, err := cs.GetAsyncJobResult(job.JobID, int64(timeout.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+
+	var r struct {
+		VirtualMachine cloudstack.VirtualMachine `json:"virtualmachine"`
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+
+	return &r.VirtualMachine, nil
+}
+
+// verifyInstanceNetworks verifies the network blocks of an instance.
+func verifyInstanceNetworks(networks []interface{}) error {
+	defaults := 0
+	for _, n := range networks {
+		if n.(map[string]interface{})["default"].(bool) {
+			defaults++
+		}
+	}
+
+	if defaults > 1 {
+		return fmt.Errorf("Only one network can be the default network, got %d", defaults)
+	}
+
+	return nil
+}
+
+// defaultNetwork returns the index of the network block that is marked as the
+// default network, or -1 if none of them is.
+func defaultNetwork(networks []interface{}) int {
+	for i, n := range networks {
+		if n.(map[string]interface{})["default"].(bool) {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateDefaultNic makes the NIC of the virtual machine in the given network
+// the default NIC.
+func updateDefaultNic(cs *providerMeta, vm *cloudstack.VirtualMachine, networkid string) error {
+	for _, nic := range vm.Nic {
+		if nic.Networkid == networkid {
+			p := cs.VirtualMachine.NewUpdateDefaultNicForVirtualMachineParams(nic.Id, vm.Id)
+			_, err := cs.VirtualMachine.UpdateDefaultNicForVirtualMachine(p)
+			return err
+		}
+	}
+
+	return fmt.Errorf("Could not find the NIC in network %s", networkid)
+}
+
+// deployedNics returns the NICs of the networks in the network blocks of an
+// instance, matched by their network ID. If there are no network blocks, like
+// after an import or when deployed with a network_id, all NICs are returned.
+func deployedNics(d *schema.ResourceData, nics []cloudstack.Nic) []cloudstack.Nic {
+	networks := d.Get("network").([]interface{})
+	if len(networks) == 0 {
+		return nics
+	}
+
+	// Count the NICs per network, as a network could be used more than once
+	counts := make(map[string]int)
+	for _, n := range networks {
+		counts[n.(map[string]interface{})["network_id"].(string)]++
+	}
+
+	var deployed []cloudstack.Nic
+	for _, nic := range nics {
+		if counts[nic.Networkid] > 0 {
+			counts[nic.Networkid]--
+			deployed = append(deployed, nic)
+		}
+	}
+
+	return deployed
+}
+
+// flattenInstanceNetworks returns the network blocks of the given NICs, in
+// the order of their devices.
+func flattenInstanceNetworks(nics []cloudstack.Nic) []interface{} {
+	sorted := append([]cloudstack.Nic(nil), nics...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, _ := strconv.Atoi(sorted[i].Deviceid)
+		b, _ := strconv.Atoi(sorted[j].Deviceid)
+		return a < b
+	})
+
+	networks := make([]interface{}, 0, len(sorted))
+	for _, nic := range sorted {
+		networks = append(networks, map[string]interface{}{
+			"network_id":   nic.Networkid,
+			"ip_address":   nic.Ipaddress,
+			"ipv6_address": nic.Ip6address,
+			"mac_address":  nic.Macaddress,
+			"default":      nic.Isdefault,
+		})
+	}
+
+	return networks
+}
+
+// joinSet returns the values of a set of strings as a comma separated list.
+func joinSet(s *schema.Set) string {
+	var values []string
+	for _, v := range s.List() {
+		values = append(values, v.(string))
+	}
+	return strings.Join(values, ",")
+}
+
+// forceNewOnPlacementChange forces a new instance when the given placement
+// key is set to a new value, unless the instance may be migrated instead.
+func forceNewOnPlacementChange(key string) schema.CustomizeDiffFunc {
+	return customdiff.ForceNewIf(key, func(d *schema.ResourceDiff, meta interface{}) bool {
+		return d.HasChange(key) && d.Get(key).(string) != "" &&
+			!d.Get("migrate_on_placement_change").(bool)
+	})
+}
+
+// validateMigration rejects a placement change of a stopped instance, as
+// only running instances can be migrated to another host.
+func validateMigration(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.Get("migrate_on_placement_change").(bool) ||
+		d.Get("state").(string) != "Stopped" {
+		return nil
+	}
+
+	for _, key := range []string{"host_id", "cluster_id", "pod_id"} {
+		if d.HasChange(key) && d.Get(key).(string) != "" {
+			return fmt.Errorf(
+				"Changing %s of stopped instance %s requires it to be running, "+
+					"as only running instances can be migrated", key, d.Get("name").(string))
+		}
+	}
+
+	return nil
+}
+
+// migrationHost returns the ID of the host to migrate the instance to, or an
+// empty string if the instance already runs on a host matching its placement.
+func migrationHost(cs *providerMeta, d *schema.ResourceData) (string, error) {
+	o, n := d.GetChange("host_id")
+	if hostid := n.(string); d.HasChange("host_id") && hostid != "" {
+		return hostid, nil
+	}
+
+	clusterid := d.Get("cluster_id").(string)
+	podid := d.Get("pod_id").(string)
+	if clusterid == "" && podid == "" {
+		return "", nil
+	}
+
+	// Create a new parameter struct
+	p := cs.Host.NewListHostsParams()
+	p.SetType("Routing")
+	p.SetState("Up")
+	if clusterid != "" {
+		p.SetClusterid(clusterid)
+	}
+	if podid != "" {
+		p.SetPodid(podid)
+	}
+
+	l, err := cs.Host.ListHosts(p)
+	if err != nil {
+		return "", err
+	}
+
+	if l.Count == 0 {
+		return "", fmt.Errorf("No hosts are up in the given cluster or pod")
+	}
+
+	for _, h := range l.Hosts {
+		if h.Id == o.(string) {
+			return "", nil
+		}
+	}
+
+	return l.Hosts[0].Id, nil
+}
+
+// instanceServiceOffering returns the ID of the service offering of the
+// instance, together with its custom resources if the offering is customized.
+func instanceServiceOffering(cs *providerMeta, d *schema.ResourceData) (string, map[string]string, error) {
+	// Retrieve the zone ID
+	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+	if e != nil {
+		return "", nil, e.Error()
+	}
+
+	scope, err := lookupScope(cs, d, zoneid)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Retrieve the service_offering ID
+	serviceofferingid, e := retrieveScopedID(cs, "service_offering", d.Get("service_offering").(string), scope)
+	if e != nil {
+		return "", nil, e.Error()
+	}
+
+	details, err := serviceOfferingDetails(cs, d, serviceofferingid)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return serviceofferingid, details, nil
+}
+
+// serviceOfferingDetails returns the custom CPU and memory details to use with
+// the given service offering, or nil if the offering isn't customized.
+func serviceOfferingDetails(
+	cs *providerMeta, d *schema.ResourceData, serviceofferingid string) (map[string]string, error) {
+	o, _, err := cs.ServiceOffering.GetServiceOfferingByID(serviceofferingid)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Error retrieving service offering %s: %s", d.Get("service_offering").(string), err)
+	}
+
+	if !o.Iscustomized {
+		return nil, nil
+	}
+
+	details := make(map[string]string)
+	for k, detail := range map[string]string{
+		"cpu_number": "cpuNumber",
+		"cpu_speed":  "cpuSpeed",
+		"memory":     "memory",
+	} {
+		if v, ok := d.GetOk(k); ok {
+			details[detail] = strconv.Itoa(v.(int))
+		}
+	}
+
+	return details, nil
+}
+
+// shrinksInstance returns true if the given service offering and custom
+// resources give the virtual machine fewer CPUs, a lower CPU speed or less
+// memory than it has now. CloudStack only scales running instances up.
+func shrinksInstance(
+	cs *providerMeta, vm *cloudstack.VirtualMachine, serviceofferingid string, details map[string]string) (bool, error) {
+	o, _, err := cs.ServiceOffering.GetServiceOfferingByID(serviceofferingid)
+	if err != nil {
+		return false, fmt.Errorf("Error retrieving service offering %s: %s", serviceofferingid, err)
+	}
+
+	cpunumber, cpuspeed, memory := o.Cpunumber, o.Cpuspeed, o.Memory
+	if o.Iscustomized {
+		cpunumber, cpuspeed, memory = vm.Cpunumber, vm.Cpuspeed, vm.Memory
+		for detail, v := range map[string]*int{
+			"cpuNumber": &cpunumber,
+			"cpuSpeed":  &cpuspeed,
+			"memory":    &memory,
+		} {
+			if value, ok := details[detail]; ok {
+				*v, _ = strconv.Atoi(value)
+			}
+		}
+	}
+
+	return cpunumber < vm.Cpunumber || cpuspeed < vm.Cpuspeed || memory < vm.Memory, nil
+}
+
+// getRootDisk returns the root disk of the instance, or nil if it can't be found.
+func getRootDisk(cs *providerMeta, d *schema.ResourceData) (*cloudstack.Volume, error) {
+	// Create a new param struct.
+	p := cs.Volume.NewListVolumesParams()
+	p.SetType("ROOT")
+	p.SetVirtualmachineid(d.Id())
+
+	// If there is a project supplied, we retrieve and set the project id
+	if err := setProjectid(p, cs, d); err != nil {
+		return nil, err
+	}
+
+	l, err := cs.Volume.ListVolumes(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(l.Volumes) != 1 {
+		return nil, nil
+	}
+
+	return l.Volumes[0], nil
+}
+
+// growRootDisk resizes the root disk of the instance to root_disk_size, if the
+// root disk is smaller than that. The root disk is never shrunk.
+func growRootDisk(cs *providerMeta, d *schema.ResourceData) error {
+	size := int64(d.Get("root_disk_size").(int))
+	if size == 0 {
+		return nil
+	}
+
+	root, err := getRootDisk(cs, d)
+	if err != nil {
+		return err
+	}
+
+	if root == nil {
+		return fmt.Errorf("Unable to find the root disk")
+	}
+
+	if root.Size>>30 >= size {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Resizing root disk %s of instance %s to %d GiB", root.Id, d.Id(), size)
+
+	p := cs.Volume.NewResizeVolumeParams(root.Id)
+	p.SetSize(size)
+
+	_, err = cs.Volume.ResizeVolume(p)
+	return err
+}
+
+// stopInstance stops the virtual machine, forcing the stop if force_stop is set.
+func stopInstance(cs *providerMeta, d *schema.ResourceData) error {
+	p := cs.VirtualMachine.NewStopVirtualMachineParams(d.Id())
+	p.SetForced(d.Get("force_stop").(bool))
+
+	_, err := cs.VirtualMachine.StopVirtualMachine(p)
+	return err
+}
+
 // getUserData returns the user data as a base64 encoded string
 func getUserData(userData string, httpGetOnly bool) (string, error) {
 	ud := userData
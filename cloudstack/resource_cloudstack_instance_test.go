@@ -21,6 +21,8 @@ package cloudstack
 
 import (
 	"fmt"
+	"os"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -71,6 +73,61 @@ func TestAccCloudStackInstance_stopped(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackInstance_state(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_stateStopped,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceState(&instance, "Stopped"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "state", "Stopped"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_stateRunning,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceState(&instance, "Running"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "state", "Running"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_stateReboot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceState(&instance, "Running"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "reboot_trigger.image", "v2"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_stateStopped,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceState(&instance, "Stopped"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "state", "Stopped"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackInstance_update(t *testing.T) {
 	var instance cloudstack.VirtualMachine
 
@@ -108,6 +165,215 @@ func TestAccCloudStackInstance_update(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackInstance_rootDisk(t *testing.T) {
+	var first, instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_rootDisk,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &first),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "root_disk_size", "12"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_rootDiskResized,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceReplaced(&first, &instance, false),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "root_disk_size", "15"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_rootDiskRebuilt,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceReplaced(&first, &instance, false),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "template", "CentOS 7 (64-bit) no GUI (Simulator)"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "root_disk_size", "25"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_rootDiskShrunk,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceReplaced(&first, &instance, true),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "root_disk_size", "22"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstance_customOffering(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_customOffering,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "cpu_number", "2"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "cpu_speed", "1000"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "memory", "2048"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "details.rootDiskController", "scsi"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_customOfferingScaled,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "cpu_number", "2"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "memory", "4096"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_customOfferingFixed,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "service_offering", "Medium Instance"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "cpu_number", "1"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "memory", "1024"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstance_customOfferingReboot(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_customOfferingReboot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "cpu_number", "1"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "memory", "1024"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_customOfferingRebootScaled,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "cpu_number", "2"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "memory", "1024"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackInstance_placement(t *testing.T) {
+	// The hosts are looked up before the test case is run, so the
+	// pre-check is done here instead of by the test case
+	testAccPreCheck(t)
+	hosts := testAccCloudStackInstanceHosts(t)
+
+	var first, instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCloudStackInstance_placementHost, hosts[0].Id),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &first),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "host_id", hosts[0].Id),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "hypervisor", hosts[0].Hypervisor),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccCloudStackInstance_placementHost, hosts[1].Id),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceReplaced(&first, &instance, false),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "host_id", hosts[1].Id),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccCloudStackInstance_placementCluster, hosts[2].Clusterid),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceReplaced(&first, &instance, false),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "host_id", hosts[2].Id),
+				),
+			},
+
+			{
+				Config: fmt.Sprintf(testAccCloudStackInstance_placementStopped, hosts[2].Id),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceReplaced(&first, &instance, false),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "state", "Stopped"),
+				),
+			},
+
+			{
+				Config:      fmt.Sprintf(testAccCloudStackInstance_placementStopped, hosts[0].Id),
+				ExpectError: regexp.MustCompile("errors during plan: .*requires it to be running"),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackInstance_fixedIP(t *testing.T) {
 	var instance cloudstack.VirtualMachine
 
@@ -129,6 +395,49 @@ func TestAccCloudStackInstance_fixedIP(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackInstance_networks(t *testing.T) {
+	var instance, after cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackInstance_networks,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceNetworks(&instance),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "network.#", "2"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "network.0.ip_address", "10.1.1.10"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "network.0.default", "false"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "network.1.ip_address", "10.1.2.20"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "network.1.mac_address", "02:00:4c:5e:00:01"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "network.1.default", "true"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_networksNIC,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &after),
+					testAccCheckCloudStackInstanceReplaced(&instance, &after, false),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "network.#", "2"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackInstance_keyPair(t *testing.T) {
 	var instance cloudstack.VirtualMachine
 
@@ -171,6 +480,56 @@ func TestAccCloudStackInstance_project(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackInstance_validateReferences(t *testing.T) {
+	var instance cloudstack.VirtualMachine
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(
+					testAccCloudStackInstance_validateReferences, "Unknown Instance", "CentOS 5.6 (64-bit) no GUI (Simulator)", `"Sandbox-simulator"`, ""),
+				ExpectError: regexp.MustCompile("errors during plan: .*Error retrieving ID of service_offering Unknown Instance"),
+			},
+
+			{
+				Config: fmt.Sprintf(
+					testAccCloudStackInstance_validateReferences, "Small Instance", "Unknown Template", `"Sandbox-simulator"`, ""),
+				ExpectError: regexp.MustCompile("errors during plan: .*Error retrieving ID of template Unknown Template"),
+			},
+
+			{
+				Config: fmt.Sprintf(
+					testAccCloudStackInstance_validateReferences, "Small Instance", "CentOS 5.6 (64-bit) no GUI (Simulator)", `"Sandbox-simulator"`, "root_disk_size = 5"),
+				ExpectError: regexp.MustCompile("errors during plan: .*root_disk_size of 5 GiB is smaller than the size of template"),
+			},
+
+			{
+				Config: fmt.Sprintf(
+					testAccCloudStackInstance_validateReferences, "Small Instance", "CentOS 5.6 (64-bit) no GUI (Simulator)", `"Sandbox-simulator"`, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackInstanceExists(
+						"cloudstack_instance.foobar", &instance),
+					testAccCheckCloudStackInstanceAttributes(&instance),
+				),
+			},
+
+			{
+				Config: testAccCloudStackInstance_zone + fmt.Sprintf(
+					testAccCloudStackInstance_validateReferences, "Small Instance", "CentOS 5.6 (64-bit) no GUI (Simulator)", "data.cloudstack_zone.zone.id", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(
+						"cloudstack_instance.foobar", "id", &instance.Id),
+					resource.TestCheckResourceAttr(
+						"cloudstack_instance.foobar", "zone", "Sandbox-simulator"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackInstance_import(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -224,7 +583,7 @@ func testAccCheckCloudStackInstanceExists(
 			return fmt.Errorf("No instance ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		vm, _, err := cs.VirtualMachine.GetVirtualMachineByID(
 			rs.Primary.ID,
 			cloudstack.WithProject(rs.Primary.Attributes["project"]),
@@ -234,82 +593,502 @@ func testAccCheckCloudStackInstanceExists(
 			return err
 		}
 
-		if vm.Id != rs.Primary.ID {
-			return fmt.Errorf("Instance not found")
-		}
+		if vm.Id != rs.Primary.ID {
+			return fmt.Errorf("Instance not found")
+		}
+
+		*instance = *vm
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackInstanceAttributes(
+	instance *cloudstack.VirtualMachine) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if instance.Name != "terraform-test" {
+			return fmt.Errorf("Bad name: %s", instance.Name)
+		}
+
+		if instance.Displayname != "terraform-test" {
+			return fmt.Errorf("Bad display name: %s", instance.Displayname)
+		}
+
+		if instance.Serviceofferingname != "Small Instance" {
+			return fmt.Errorf("Bad service offering: %s", instance.Serviceofferingname)
+		}
+
+		if instance.Templatename != "CentOS 5.6 (64-bit) no GUI (Simulator)" {
+			return fmt.Errorf("Bad template: %s", instance.Templatename)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackInstanceNetworks(
+	instance *cloudstack.VirtualMachine) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if len(instance.Nic) != 2 {
+			return fmt.Errorf("Bad number of NICs: %d", len(instance.Nic))
+		}
+
+		for _, nic := range instance.Nic {
+			switch nic.Ipaddress {
+			case "10.1.1.10":
+				if nic.Isdefault {
+					return fmt.Errorf("Bad default NIC: %s", nic.Ipaddress)
+				}
+			case "10.1.2.20":
+				if !nic.Isdefault {
+					return fmt.Errorf("Bad default NIC: %s", nic.Ipaddress)
+				}
+			default:
+				return fmt.Errorf("Bad IP address: %s", nic.Ipaddress)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackInstanceState(
+	instance *cloudstack.VirtualMachine, state string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if instance.State != state {
+			return fmt.Errorf("Bad state: %s", instance.State)
+		}
+
+		return nil
+	}
+}
+
+// testAccCloudStackInstanceHosts returns two hosts in the same cluster and a
+// host in another cluster, or skips the test if there are no such hosts.
+func testAccCloudStackInstanceHosts(t *testing.T) []*cloudstack.Host {
+	cfg := Config{
+		APIURL:    os.Getenv("CLOUDSTACK_API_URL"),
+		APIKey:    os.Getenv("CLOUDSTACK_API_KEY"),
+		SecretKey: os.Getenv("CLOUDSTACK_SECRET_KEY"),
+		Timeout:   60,
+	}
+
+	cs, err := cfg.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := cs.Host.NewListHostsParams()
+	p.SetType("Routing")
+	p.SetState("Up")
+
+	l, err := cs.Host.ListHosts(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, a := range l.Hosts {
+		for _, b := range l.Hosts[i+1:] {
+			if b.Clusterid != a.Clusterid {
+				continue
+			}
+			for _, c := range l.Hosts {
+				if c.Clusterid != a.Clusterid {
+					return []*cloudstack.Host{a, b, c}
+				}
+			}
+		}
+	}
+
+	t.Skip("This test requires two hosts in one cluster and a host in another cluster")
+	return nil
+}
+
+func testAccCheckCloudStackInstanceReplaced(
+	before, after *cloudstack.VirtualMachine, replaced bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if replaced && before.Id == after.Id {
+			return fmt.Errorf("Expected instance %s to be replaced", before.Id)
+		}
+
+		if !replaced && before.Id != after.Id {
+			return fmt.Errorf("Expected instance %s to be updated in place, got %s", before.Id, after.Id)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackInstanceRenamedAndResized(
+	instance *cloudstack.VirtualMachine) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if instance.Name != "terraform-updated" {
+			return fmt.Errorf("Bad name: %s", instance.Name)
+		}
+
+		if instance.Displayname != "terraform-updated" {
+			return fmt.Errorf("Bad display name: %s", instance.Displayname)
+		}
+
+		if instance.Serviceofferingname != "Medium Instance" {
+			return fmt.Errorf("Bad service offering: %s", instance.Serviceofferingname)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackInstanceDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*providerMeta)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_instance" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No instance ID is set")
+		}
+
+		_, _, err := cs.VirtualMachine.GetVirtualMachineByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Virtual Machine %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackInstance_basic = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  user_data = "foobar\nfoo\nbar"
+  expunge = true
+  tags = {
+    terraform-tag = "true"
+  }
+}`
+
+const testAccCloudStackInstance_stopped = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  start_vm = false
+  expunge = true
+}`
+
+const testAccCloudStackInstance_stateStopped = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  state = "Stopped"
+  force_stop = true
+  expunge = true
+
+  reboot_trigger = {
+    image = "v1"
+  }
+}`
+
+const testAccCloudStackInstance_stateRunning = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  state = "Running"
+  expunge = true
+
+  reboot_trigger = {
+    image = "v1"
+  }
+}`
+
+const testAccCloudStackInstance_stateReboot = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  state = "Running"
+  expunge = true
+
+  reboot_trigger = {
+    image = "v2"
+  }
+}`
+
+const testAccCloudStackInstance_renameAndResize = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-updated"
+  display_name = "terraform-updated"
+  service_offering= "Medium Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  user_data = "foobar\nfoo\nbar"
+  expunge = true
+}`
+
+const testAccCloudStackInstance_rootDisk = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
 
-		*instance = *vm
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  rebuild_on_template_change = true
+  root_disk_size = 12
+  zone = "Sandbox-simulator"
+  expunge = true
+}`
 
-		return nil
-	}
+const testAccCloudStackInstance_rootDiskResized = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
 }
 
-func testAccCheckCloudStackInstanceAttributes(
-	instance *cloudstack.VirtualMachine) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  rebuild_on_template_change = true
+  root_disk_size = 15
+  zone = "Sandbox-simulator"
+  expunge = true
+}`
 
-		if instance.Name != "terraform-test" {
-			return fmt.Errorf("Bad name: %s", instance.Name)
-		}
+const testAccCloudStackInstance_rootDiskRebuilt = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
 
-		if instance.Displayname != "terraform-test" {
-			return fmt.Errorf("Bad display name: %s", instance.Displayname)
-		}
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 7 (64-bit) no GUI (Simulator)"
+  rebuild_on_template_change = true
+  root_disk_size = 25
+  zone = "Sandbox-simulator"
+  expunge = true
+}`
 
-		if instance.Serviceofferingname != "Small Instance" {
-			return fmt.Errorf("Bad service offering: %s", instance.Serviceofferingname)
-		}
+const testAccCloudStackInstance_rootDiskShrunk = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
 
-		if instance.Templatename != "CentOS 5.6 (64-bit) no GUI (Simulator)" {
-			return fmt.Errorf("Bad template: %s", instance.Templatename)
-		}
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 7 (64-bit) no GUI (Simulator)"
+  rebuild_on_template_change = true
+  root_disk_size = 22
+  zone = "Sandbox-simulator"
+  expunge = true
+}`
 
-		return nil
-	}
+const testAccCloudStackInstance_customOffering = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
 }
 
-func testAccCheckCloudStackInstanceRenamedAndResized(
-	instance *cloudstack.VirtualMachine) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Custom Instance"
+  cpu_number = 2
+  cpu_speed = 1000
+  memory = 2048
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 7 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
 
-		if instance.Name != "terraform-updated" {
-			return fmt.Errorf("Bad name: %s", instance.Name)
-		}
+  details = {
+    rootDiskController = "scsi"
+  }
+}`
 
-		if instance.Displayname != "terraform-updated" {
-			return fmt.Errorf("Bad display name: %s", instance.Displayname)
-		}
+const testAccCloudStackInstance_customOfferingScaled = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
 
-		if instance.Serviceofferingname != "Medium Instance" {
-			return fmt.Errorf("Bad service offering: %s", instance.Serviceofferingname)
-		}
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Custom Instance"
+  cpu_number = 2
+  cpu_speed = 1000
+  memory = 4096
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 7 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
 
-		return nil
-	}
+  details = {
+    rootDiskController = "scsi"
+  }
+}`
+
+const testAccCloudStackInstance_customOfferingFixed = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
 }
 
-func testAccCheckCloudStackInstanceDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Medium Instance"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 7 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
 
-	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "cloudstack_instance" {
-			continue
-		}
+  details = {
+    rootDiskController = "scsi"
+  }
+}`
 
-		if rs.Primary.ID == "" {
-			return fmt.Errorf("No instance ID is set")
-		}
+const testAccCloudStackInstance_customOfferingReboot = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
 
-		_, _, err := cs.VirtualMachine.GetVirtualMachineByID(rs.Primary.ID)
-		if err == nil {
-			return fmt.Errorf("Virtual Machine %s still exists", rs.Primary.ID)
-		}
-	}
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Custom Instance"
+  cpu_number = 1
+  cpu_speed = 500
+  memory = 1024
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+}`
 
-	return nil
+const testAccCloudStackInstance_customOfferingRebootScaled = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
 }
 
-const testAccCloudStackInstance_basic = `
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Custom Instance"
+  cpu_number = 2
+  cpu_speed = 500
+  memory = 1024
+  network_id = "${cloudstack_network.foo.id}"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+}`
+
+const testAccCloudStackInstance_placementHost = `
 resource "cloudstack_network" "foo" {
   name = "terraform-network"
   cidr = "10.1.1.0/24"
@@ -324,14 +1103,13 @@ resource "cloudstack_instance" "foobar" {
   network_id = "${cloudstack_network.foo.id}"
   template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
   zone = "Sandbox-simulator"
-  user_data = "foobar\nfoo\nbar"
+  host_id = "%s"
+  deployment_planner = "FirstFitPlanner"
+  migrate_on_placement_change = true
   expunge = true
-  tags = {
-    terraform-tag = "true"
-  }
 }`
 
-const testAccCloudStackInstance_stopped = `
+const testAccCloudStackInstance_placementCluster = `
 resource "cloudstack_network" "foo" {
   name = "terraform-network"
   cidr = "10.1.1.0/24"
@@ -346,11 +1124,13 @@ resource "cloudstack_instance" "foobar" {
   network_id = "${cloudstack_network.foo.id}"
   template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
   zone = "Sandbox-simulator"
-  start_vm = false
+  cluster_id = "%s"
+  deployment_planner = "FirstFitPlanner"
+  migrate_on_placement_change = true
   expunge = true
 }`
 
-const testAccCloudStackInstance_renameAndResize = `
+const testAccCloudStackInstance_placementStopped = `
 resource "cloudstack_network" "foo" {
   name = "terraform-network"
   cidr = "10.1.1.0/24"
@@ -359,13 +1139,16 @@ resource "cloudstack_network" "foo" {
 }
 
 resource "cloudstack_instance" "foobar" {
-  name = "terraform-updated"
-  display_name = "terraform-updated"
-  service_offering= "Medium Instance"
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
   network_id = "${cloudstack_network.foo.id}"
   template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
   zone = "Sandbox-simulator"
-  user_data = "foobar\nfoo\nbar"
+  host_id = "%s"
+  deployment_planner = "FirstFitPlanner"
+  migrate_on_placement_change = true
+  state = "Stopped"
   expunge = true
 }`
 
@@ -388,6 +1171,56 @@ resource "cloudstack_instance" "foobar" {
   expunge = true
 }`
 
+const testAccCloudStackInstance_networks = `
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_network" "bar" {
+  name = "terraform-network-bar"
+  cidr = "10.1.2.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "Small Instance"
+  template = "CentOS 5.6 (64-bit) no GUI (Simulator)"
+  zone = "Sandbox-simulator"
+  expunge = true
+
+  network {
+    network_id = "${cloudstack_network.foo.id}"
+    ip_address = "10.1.1.10"
+  }
+
+  network {
+    network_id = "${cloudstack_network.bar.id}"
+    ip_address = "10.1.2.20"
+    mac_address = "02:00:4c:5e:00:01"
+    default = true
+  }
+}`
+
+const testAccCloudStackInstance_networksNIC = testAccCloudStackInstance_networks + `
+
+resource "cloudstack_network" "baz" {
+  name = "terraform-network-baz"
+  cidr = "10.1.3.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_nic" "foo" {
+  network_id = "${cloudstack_network.baz.id}"
+  virtual_machine_id = "${cloudstack_instance.foobar.id}"
+}`
+
 const testAccCloudStackInstance_keyPair = `
 resource "cloudstack_network" "foo" {
   name = "terraform-network"
@@ -430,3 +1263,39 @@ resource "cloudstack_instance" "foobar" {
   zone = "${cloudstack_network.foo.zone}"
   expunge = true
 }`
+
+const testAccCloudStackInstance_validateReferences = `
+provider "cloudstack" {
+  validate_references_at_plan = true
+}
+
+resource "cloudstack_network" "foo" {
+  name = "terraform-network"
+  cidr = "10.1.1.0/24"
+  network_offering = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+  zone = "Sandbox-simulator"
+}
+
+resource "cloudstack_instance" "foobar" {
+  name = "terraform-test"
+  display_name = "terraform-test"
+  service_offering= "%s"
+  network_id = "${cloudstack_network.foo.id}"
+  template = "%s"
+  zone = %s
+  user_data = "foobar\nfoo\nbar"
+  expunge = true
+  %s
+  tags = {
+    terraform-tag = "true"
+  }
+}`
+
+const testAccCloudStackInstance_zone = `
+data "cloudstack_zone" "zone" {
+  filter {
+    name = "name"
+    value = "Sandbox-simulator"
+  }
+}
+`
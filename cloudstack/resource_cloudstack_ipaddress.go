@@ -22,7 +22,6 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -33,6 +32,9 @@ func resourceCloudStackIPAddress() *schema.Resource {
 		Create: resourceCloudStackIPAddressCreate,
 		Read:   resourceCloudStackIPAddressRead,
 		Delete: resourceCloudStackIPAddressDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackIPAddressImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"is_portable": {
@@ -57,6 +59,7 @@ func resourceCloudStackIPAddress() *schema.Resource {
 			"zone": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
 
@@ -67,6 +70,21 @@ func resourceCloudStackIPAddress() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
 			"ip_address": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -78,7 +96,7 @@ func resourceCloudStackIPAddress() *schema.Resource {
 }
 
 func resourceCloudStackIPAddressCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	if err := verifyIPAddressParams(d); err != nil {
 		return err
@@ -117,6 +135,11 @@ func resourceCloudStackIPAddressCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// Associate a new IP address
 	r, err := cs.Address.AssociateIpAddress(p)
 	if err != nil {
@@ -135,15 +158,16 @@ func resourceCloudStackIPAddressCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceCloudStackIPAddressRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the IP address details
 	ip, count, err := cs.Address.GetPublicIpAddressByID(
 		d.Id(),
 		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf(
 				"[DEBUG] IP address with ID %s is no longer associated", d.Id())
 			d.SetId("")
@@ -166,9 +190,7 @@ func resourceCloudStackIPAddressRead(d *schema.ResourceData, meta interface{}) e
 		d.Set("vpc_id", ip.Vpcid)
 	}
 
-	if _, ok := d.GetOk("zone"); ok {
-		setValueOrID(d, "zone", ip.Zonename, ip.Zoneid)
-	}
+	setValueOrID(d, "zone", ip.Zonename, ip.Zoneid)
 
 	tags := make(map[string]interface{})
 	for _, tag := range ip.Tags {
@@ -177,22 +199,24 @@ func resourceCloudStackIPAddressRead(d *schema.ResourceData, meta interface{}) e
 	d.Set("tags", tags)
 
 	setValueOrID(d, "project", ip.Project, ip.Projectid)
+	setAccountAndDomain(d, ip.Account, ip.Domain, ip.Domainid)
 
 	return nil
 }
 
 func resourceCloudStackIPAddressDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.Address.NewDisassociateIpAddressParams(d.Id())
 
 	// Disassociate the IP address
-	if _, err := cs.Address.DisassociateIpAddress(p); err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.Address.DisassociateIpAddress(p)
+		return err
+	})
+	if err != nil {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -202,6 +226,34 @@ func resourceCloudStackIPAddressDelete(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+func resourceCloudStackIPAddressImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*providerMeta)
+
+	// Split off the optional project, or domain and account
+	if _, err := importStatePassthrough(d, meta); err != nil {
+		return nil, err
+	}
+
+	ip, _, err := cs.Address.GetPublicIpAddressByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving IP address %s: %s", d.Id(), err)
+	}
+
+	// The network and VPC are only read when they are configured, so set
+	// the one the IP address is associated with
+	if ip.Vpcid != "" {
+		d.Set("vpc_id", ip.Vpcid)
+	} else {
+		d.Set("network_id", ip.Associatednetworkid)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func verifyIPAddressParams(d *schema.ResourceData) error {
 	_, portable := d.GetOk("is_portable")
 	_, network := d.GetOk("network_id")
@@ -48,6 +48,25 @@ func TestAccCloudStackIPAddress_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackIPAddress_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackIPAddressDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackIPAddress_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_ipaddress.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccCloudStackIPAddress_vpc(t *testing.T) {
 	var ipaddr cloudstack.PublicIpAddress
 
@@ -79,7 +98,7 @@ func testAccCheckCloudStackIPAddressExists(
 			return fmt.Errorf("No IP address ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		pip, _, err := cs.Address.GetPublicIpAddressByID(rs.Primary.ID)
 
 		if err != nil {
@@ -97,7 +116,7 @@ func testAccCheckCloudStackIPAddressExists(
 }
 
 func testAccCheckCloudStackIPAddressDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_ipaddress" {
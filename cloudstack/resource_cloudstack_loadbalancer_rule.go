@@ -22,9 +22,10 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
+	"strconv"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
@@ -34,6 +35,9 @@ func resourceCloudStackLoadBalancerRule() *schema.Resource {
 		Read:   resourceCloudStackLoadBalancerRuleRead,
 		Update: resourceCloudStackLoadBalancerRuleUpdate,
 		Delete: resourceCloudStackLoadBalancerRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -60,8 +64,9 @@ func resourceCloudStackLoadBalancerRule() *schema.Resource {
 			},
 
 			"algorithm": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(loadBalancerAlgorithms, false),
 			},
 
 			"certificate_id": {
@@ -70,22 +75,25 @@ func resourceCloudStackLoadBalancerRule() *schema.Resource {
 			},
 
 			"private_port": {
-				Type:     schema.TypeInt,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validatePort,
 			},
 
 			"public_port": {
-				Type:     schema.TypeInt,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validatePort,
 			},
 
 			"protocol": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(loadBalancerProtocols, false),
 			},
 
 			"member_ids": {
@@ -102,12 +110,27 @@ func resourceCloudStackLoadBalancerRule() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 		},
 	}
 }
 
 func resourceCloudStackLoadBalancerRuleCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Make sure all required parameters are there
 	if err := verifyLoadBalancerRule(d); err != nil {
@@ -147,6 +170,11 @@ func resourceCloudStackLoadBalancerRuleCreate(d *schema.ResourceData, meta inter
 	// Set the ipaddress id
 	p.SetPublicipid(d.Get("ip_address_id").(string))
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// Create the load balancer rule
 	r, err := cs.LoadBalancer.CreateLoadBalancerRule(p)
 	if err != nil {
@@ -195,15 +223,16 @@ func resourceCloudStackLoadBalancerRuleCreate(d *schema.ResourceData, meta inter
 }
 
 func resourceCloudStackLoadBalancerRuleRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the load balancer details
 	lb, count, err := cs.LoadBalancer.GetLoadBalancerRuleByID(
 		d.Id(),
 		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] Load balancer rule %s does no longer exist", d.Get("name").(string))
 			d.SetId("")
 			return nil
@@ -215,8 +244,15 @@ func resourceCloudStackLoadBalancerRuleRead(d *schema.ResourceData, meta interfa
 	d.Set("name", lb.Name)
 	d.Set("ip_address_id", lb.Publicipid)
 	d.Set("algorithm", lb.Algorithm)
-	d.Set("public_port", lb.Publicport)
-	d.Set("private_port", lb.Privateport)
+
+	// The API returns the ports as strings
+	if port, err := strconv.Atoi(lb.Publicport); err == nil {
+		d.Set("public_port", port)
+	}
+	if port, err := strconv.Atoi(lb.Privateport); err == nil {
+		d.Set("private_port", port)
+	}
+
 	d.Set("protocol", lb.Protocol)
 
 	// Only set network if user specified it to avoid spurious diffs
@@ -225,6 +261,7 @@ func resourceCloudStackLoadBalancerRuleRead(d *schema.ResourceData, meta interfa
 	}
 
 	setValueOrID(d, "project", lb.Project, lb.Projectid)
+	setAccountAndDomain(d, lb.Account, lb.Domain, lb.Domainid)
 
 	p := cs.LoadBalancer.NewListLoadBalancerRuleInstancesParams(d.Id())
 	l, err := cs.LoadBalancer.ListLoadBalancerRuleInstances(p)
@@ -242,7 +279,7 @@ func resourceCloudStackLoadBalancerRuleRead(d *schema.ResourceData, meta interfa
 }
 
 func resourceCloudStackLoadBalancerRuleUpdate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Make sure all required parameters are there
 	if err := verifyLoadBalancerRule(d); err != nil {
@@ -339,19 +376,18 @@ func resourceCloudStackLoadBalancerRuleUpdate(d *schema.ResourceData, meta inter
 }
 
 func resourceCloudStackLoadBalancerRuleDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.LoadBalancer.NewDeleteLoadBalancerRuleParams(d.Id())
 
 	log.Printf("[INFO] Deleting load balancer rule: %s", d.Get("name").(string))
-	if _, err := cs.LoadBalancer.DeleteLoadBalancerRule(p); err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if !strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
-			return err
-		}
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.LoadBalancer.DeleteLoadBalancerRule(p)
+		return err
+	})
+	if err != nil && !isNotFound(err) {
+		return err
 	}
 
 	return nil
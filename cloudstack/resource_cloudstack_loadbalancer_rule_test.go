@@ -26,7 +26,6 @@ import (
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 func TestAccCloudStackLoadBalancerRule_basic(t *testing.T) {
@@ -53,6 +52,25 @@ func TestAccCloudStackLoadBalancerRule_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackLoadBalancerRule_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackLoadBalancerRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackLoadBalancerRule_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_loadbalancer_rule.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccCloudStackLoadBalancerRule_update(t *testing.T) {
 	var id string
 
@@ -217,7 +235,7 @@ func testAccCheckCloudStackLoadBalancerRuleExist(n string, id *string) resource.
 			*id = rs.Primary.ID
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		_, count, err := cs.LoadBalancer.GetLoadBalancerRuleByID(rs.Primary.ID)
 
 		if err != nil {
@@ -233,7 +251,7 @@ func testAccCheckCloudStackLoadBalancerRuleExist(n string, id *string) resource.
 }
 
 func testAccCheckCloudStackLoadBalancerRuleDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_loadbalancer_rule" {
@@ -24,7 +24,6 @@ import (
 	"log"
 	"net"
 	"strconv"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -60,6 +59,14 @@ func resourceCloudStackNetwork() *schema.Resource {
 			State: importStatePassthrough,
 		},
 
+		CustomizeDiff: validateReferences("network_offering", "project", "zone"),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: providerTimeout(),
+			Update: providerTimeout(),
+			Delete: providerTimeout(),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -73,30 +80,34 @@ func resourceCloudStackNetwork() *schema.Resource {
 			},
 
 			"cidr": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateCIDR,
 			},
 
 			"gateway": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPAddress,
 			},
 
 			"startip": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPAddress,
 			},
 
 			"endip": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPAddress,
 			},
 
 			"network_domain": {
@@ -131,6 +142,21 @@ func resourceCloudStackNetwork() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
 			"source_nat_ip": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -154,19 +180,25 @@ func resourceCloudStackNetwork() *schema.Resource {
 }
 
 func resourceCloudStackNetworkCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutCreate))
+
 	d.Partial(true)
 
 	name := d.Get("name").(string)
 
-	// Retrieve the network_offering ID
-	networkofferingid, e := retrieveID(cs, "network_offering", d.Get("network_offering").(string))
+	// Retrieve the zone ID
+	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
 	if e != nil {
 		return e.Error()
 	}
 
-	// Retrieve the zone ID
-	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+	scope, err := lookupScope(cs, d, zoneid)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve the network_offering ID
+	networkofferingid, e := retrieveScopedID(cs, "network_offering", d.Get("network_offering").(string), scope)
 	if e != nil {
 		return e.Error()
 	}
@@ -231,6 +263,11 @@ func resourceCloudStackNetworkCreate(d *schema.ResourceData, meta interface{}) e
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// Create the new network
 	r, err := cs.Network.CreateNetwork(p)
 	if err != nil {
@@ -277,6 +314,11 @@ func resourceCloudStackNetworkCreate(d *schema.ResourceData, meta interface{}) e
 			return err
 		}
 
+		// If there is an account supplied, we retrieve and set the account and domain id
+		if err := setAccountid(p, cs, d); err != nil {
+			return err
+		}
+
 		// Associate a new IP address
 		ip, err := cs.Address.AssociateIpAddress(p)
 		if err != nil {
@@ -294,15 +336,16 @@ func resourceCloudStackNetworkCreate(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceCloudStackNetworkRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the virtual machine details
 	n, count, err := cs.Network.GetNetworkByID(
 		d.Id(),
 		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf(
 				"[DEBUG] Network %s does no longer exist", d.Get("name").(string))
 			d.SetId("")
@@ -332,15 +375,17 @@ func resourceCloudStackNetworkRead(d *schema.ResourceData, meta interface{}) err
 
 	setValueOrID(d, "network_offering", n.Networkofferingname, n.Networkofferingid)
 	setValueOrID(d, "project", n.Project, n.Projectid)
+	setAccountAndDomain(d, n.Account, n.Domain, n.Domainid)
 	setValueOrID(d, "zone", n.Zonename, n.Zoneid)
 
 	if d.Get("source_nat_ip").(bool) {
 		ip, count, err := cs.Address.GetPublicIpAddressByID(
 			d.Get("source_nat_ip_id").(string),
 			cloudstack.WithProject(d.Get("project").(string)),
+			withAccount(cs, d),
 		)
 		if err != nil {
-			if count == 0 {
+			if count == 0 || isNotFound(err) {
 				log.Printf(
 					"[DEBUG] Source NAT IP with ID %s is no longer associated", d.Id())
 				d.Set("source_nat_ip", false)
@@ -361,7 +406,8 @@ func resourceCloudStackNetworkRead(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceCloudStackNetworkUpdate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutUpdate))
+
 	name := d.Get("name").(string)
 
 	// Create a new parameter struct
@@ -391,8 +437,19 @@ func resourceCloudStackNetworkUpdate(d *schema.ResourceData, meta interface{}) e
 
 	// Check if the network offering is changed
 	if d.HasChange("network_offering") {
+		// Retrieve the zone ID
+		zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+		if e != nil {
+			return e.Error()
+		}
+
+		scope, err := lookupScope(cs, d, zoneid)
+		if err != nil {
+			return err
+		}
+
 		// Retrieve the network_offering ID
-		networkofferingid, e := retrieveID(cs, "network_offering", d.Get("network_offering").(string))
+		networkofferingid, e := retrieveScopedID(cs, "network_offering", d.Get("network_offering").(string), scope)
 		if e != nil {
 			return e.Error()
 		}
@@ -429,18 +486,18 @@ func resourceCloudStackNetworkUpdate(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceCloudStackNetworkDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutDelete))
 
 	// Create a new parameter struct
 	p := cs.Network.NewDeleteNetworkParams(d.Id())
 
 	// Delete the network
-	_, err := cs.Network.DeleteNetwork(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.Network.DeleteNetwork(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -25,10 +25,10 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
@@ -38,6 +38,9 @@ func resourceCloudStackNetworkACLRule() *schema.Resource {
 		Read:   resourceCloudStackNetworkACLRuleRead,
 		Update: resourceCloudStackNetworkACLRuleUpdate,
 		Delete: resourceCloudStackNetworkACLRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackNetworkACLRuleImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"acl_id": {
@@ -58,46 +61,57 @@ func resourceCloudStackNetworkACLRule() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"action": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "allow",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "allow",
+							ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
 						},
 
 						"cidr_list": {
 							Type:     schema.TypeSet,
 							Required: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-							Set:      schema.HashString,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateCIDR,
+							},
+							Set: schema.HashString,
 						},
 
 						"protocol": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateProtocol("tcp", "udp", "icmp", "all"),
 						},
 
 						"icmp_type": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateICMP,
 						},
 
 						"icmp_code": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateICMP,
 						},
 
 						"ports": {
 							Type:     schema.TypeSet,
 							Optional: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-							Set:      schema.HashString,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validatePortRange,
+							},
+							Set: schema.HashString,
 						},
 
 						"traffic_type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "ingress",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "ingress",
+							ValidateFunc: validation.StringInSlice(trafficTypes, false),
 						},
 
 						"uuids": {
@@ -115,9 +129,10 @@ func resourceCloudStackNetworkACLRule() *schema.Resource {
 			},
 
 			"parallelism": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  2,
+				Type:       schema.TypeInt,
+				Optional:   true,
+				Default:    2,
+				Deprecated: "API calls are now limited by the provider wide 'max_requests_per_second' and 'max_concurrent_requests' arguments",
 			},
 		},
 	}
@@ -156,28 +171,26 @@ func createNetworkACLRules(d *schema.ResourceData, meta interface{}, rules *sche
 	var wg sync.WaitGroup
 	wg.Add(nrs.Len())
 
-	sem := make(chan struct{}, d.Get("parallelism").(int))
+	var mu sync.Mutex
 	for _, rule := range nrs.List() {
-		// Put in a tiny sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Create a single rule
 			err := createNetworkACLRule(d, meta, rule)
 
 			// If we have at least one UUID, we need to save the rule
 			if len(rule["uuids"].(map[string]interface{})) > 0 {
+				mu.Lock()
 				rules.Add(rule)
+				mu.Unlock()
 			}
 
 			if err != nil {
+				mu.Lock()
 				errs = multierror.Append(errs, err)
+				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -187,7 +200,7 @@ func createNetworkACLRules(d *schema.ResourceData, meta interface{}, rules *sche
 }
 
 func createNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 	uuids := rule["uuids"].(map[string]interface{})
 
 	// Make sure all required parameters are there
@@ -254,6 +267,10 @@ func createNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[str
 				}
 
 				m := splitPorts.FindStringSubmatch(port.(string))
+				if m == nil {
+					return fmt.Errorf(
+						"%q is not a valid port value. Valid options are '80' or '80-90'", port.(string))
+				}
 
 				startPort, err := strconv.Atoi(m[1])
 				if err != nil {
@@ -289,7 +306,7 @@ func createNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[str
 }
 
 func resourceCloudStackNetworkACLRuleRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// First check if the ACL itself still exists
 	_, count, err := cs.NetworkACL.GetNetworkACLListByID(
@@ -297,7 +314,7 @@ func resourceCloudStackNetworkACLRuleRead(d *schema.ResourceData, meta interface
 		cloudstack.WithProject(d.Get("project").(string)),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf(
 				"[DEBUG] Network ACL list %s does no longer exist", d.Id())
 			d.SetId("")
@@ -538,34 +555,93 @@ func resourceCloudStackNetworkACLRuleDelete(d *schema.ResourceData, meta interfa
 	return nil
 }
 
+func resourceCloudStackNetworkACLRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*providerMeta)
+
+	// Split off the optional project
+	if _, err := importStatePassthrough(d, meta); err != nil {
+		return nil, err
+	}
+
+	// Make sure the ACL exists
+	_, _, err := cs.NetworkACL.GetNetworkACLListByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving network ACL list %s: %s", d.Id(), err)
+	}
+
+	// Get all the rules from the running environment
+	p := cs.NetworkACL.NewListNetworkACLsParams()
+	p.SetAclid(d.Id())
+	p.SetListall(true)
+
+	l, err := cs.NetworkACL.ListNetworkACLs(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []ruleSetRule
+	for _, r := range l.NetworkACLs {
+		startPort, _ := strconv.Atoi(r.Startport)
+		endPort, _ := strconv.Atoi(r.Endport)
+
+		rs = append(rs, ruleSetRule{
+			id:        r.Id,
+			protocol:  strings.ToLower(r.Protocol),
+			cidrs:     splitList(r.Cidrlist),
+			startPort: startPort,
+			endPort:   endPort,
+			icmpType:  r.Icmptype,
+			icmpCode:  r.Icmpcode,
+			attrs: map[string]interface{}{
+				"action":       strings.ToLower(r.Action),
+				"traffic_type": strings.ToLower(r.Traffictype),
+			},
+		})
+	}
+
+	// Group the rules back into the rules they were created from
+	rules := resourceCloudStackNetworkACLRule().Schema["rule"].ZeroValue().(*schema.Set)
+	for _, rule := range groupRules(rs) {
+		rules.Add(rule)
+	}
+
+	// An imported ACL rule set manages all rules of the ACL
+	d.Set("acl_id", d.Id())
+	d.Set("managed", true)
+	d.Set("rule", rules)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func deleteNetworkACLRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, ors *schema.Set) error {
 	var errs *multierror.Error
 
 	var wg sync.WaitGroup
 	wg.Add(ors.Len())
 
-	sem := make(chan struct{}, d.Get("parallelism").(int))
+	var mu sync.Mutex
 	for _, rule := range ors.List() {
-		// Put a sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Delete a single rule
 			err := deleteNetworkACLRule(d, meta, rule)
 
 			// If we have at least one UUID, we need to save the rule
 			if len(rule["uuids"].(map[string]interface{})) > 0 {
+				mu.Lock()
 				rules.Add(rule)
+				mu.Unlock()
 			}
 
 			if err != nil {
+				mu.Lock()
 				errs = multierror.Append(errs, err)
+				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -575,7 +651,7 @@ func deleteNetworkACLRules(d *schema.ResourceData, meta interface{}, rules *sche
 }
 
 func deleteNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 	uuids := rule["uuids"].(map[string]interface{})
 
 	for k, id := range uuids {
@@ -590,10 +666,7 @@ func deleteNetworkACLRule(d *schema.ResourceData, meta interface{}, rule map[str
 		// Delete the rule
 		if _, err := cs.NetworkACL.DeleteNetworkACL(p); err != nil {
 
-			// This is a very poor way to be told the ID does no longer exist :(
-			if strings.Contains(err.Error(), fmt.Sprintf(
-				"Invalid parameter id value=%s due to incorrect long value format, "+
-					"or entity does not exist", id.(string))) {
+			if isNotFound(err) {
 				delete(uuids, k)
 				rule["uuids"] = uuids
 				continue
@@ -673,7 +746,7 @@ func verifyNetworkACLRuleParams(d *schema.ResourceData, rule map[string]interfac
 }
 
 func retryableACLCreationFunc(
-	cs *cloudstack.CloudStackClient,
+	cs *providerMeta,
 	p *cloudstack.CreateNetworkACLParams) func() (interface{}, error) {
 	return func() (interface{}, error) {
 		r, err := cs.NetworkACL.CreateNetworkACL(p)
@@ -170,7 +170,7 @@ func testAccCheckCloudStackNetworkExists(
 			return fmt.Errorf("No network ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		ntwrk, _, err := cs.Network.GetNetworkByID(
 			rs.Primary.ID,
 			cloudstack.WithProject(rs.Primary.Attributes["project"]),
@@ -238,7 +238,7 @@ func testAccCheckCloudStackNetworkVPCAttributes(
 }
 
 func testAccCheckCloudStackNetworkDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_network" {
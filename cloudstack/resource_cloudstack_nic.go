@@ -22,7 +22,6 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -33,6 +32,9 @@ func resourceCloudStackNIC() *schema.Resource {
 		Create: resourceCloudStackNICCreate,
 		Read:   resourceCloudStackNICRead,
 		Delete: resourceCloudStackNICDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackNICImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"network_id": {
@@ -42,10 +44,11 @@ func resourceCloudStackNIC() *schema.Resource {
 			},
 
 			"ip_address": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPAddress,
 			},
 
 			"virtual_machine_id": {
@@ -58,7 +61,7 @@ func resourceCloudStackNIC() *schema.Resource {
 }
 
 func resourceCloudStackNICCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.VirtualMachine.NewAddNicToVirtualMachineParams(
@@ -94,12 +97,12 @@ func resourceCloudStackNICCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceCloudStackNICRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the virtual machine details
 	vm, count, err := cs.VirtualMachine.GetVirtualMachineByID(d.Get("virtual_machine_id").(string))
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] Instance %s does no longer exist", d.Get("virtual_machine_id").(string))
 			d.SetId("")
 			return nil
@@ -129,7 +132,7 @@ func resourceCloudStackNICRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceCloudStackNICDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.VirtualMachine.NewRemoveNicFromVirtualMachineParams(
@@ -138,12 +141,12 @@ func resourceCloudStackNICDelete(d *schema.ResourceData, meta interface{}) error
 	)
 
 	// Remove the NIC
-	_, err := cs.VirtualMachine.RemoveNicFromVirtualMachine(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.VirtualMachine.RemoveNicFromVirtualMachine(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -153,7 +156,20 @@ func resourceCloudStackNICDelete(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
-func retryableAddNicFunc(cs *cloudstack.CloudStackClient, p *cloudstack.AddNicToVirtualMachineParams) func() (interface{}, error) {
+func resourceCloudStackNICImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// A NIC can only be found through the virtual machine it belongs to
+	virtualmachineid, id, err := splitImportID(d.Id(), "<VIRTUAL MACHINE ID>/<NIC ID>")
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("virtual_machine_id", virtualmachineid)
+	d.SetId(id)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func retryableAddNicFunc(cs *providerMeta, p *cloudstack.AddNicToVirtualMachineParams) func() (interface{}, error) {
 	return func() (interface{}, error) {
 		r, err := cs.VirtualMachine.AddNicToVirtualMachine(p)
 		if err != nil {
@@ -48,6 +48,35 @@ func TestAccCloudStackNIC_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackNIC_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackNICDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackNIC_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_nic.foo",
+				ImportState:       true,
+				ImportStateIdFunc: testAccCloudStackNICImportStateID,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCloudStackNICImportStateID(s *terraform.State) (string, error) {
+	rs, ok := s.RootModule().Resources["cloudstack_nic.foo"]
+	if !ok {
+		return "", fmt.Errorf("Not found: cloudstack_nic.foo")
+	}
+
+	return fmt.Sprintf("%s/%s", rs.Primary.Attributes["virtual_machine_id"], rs.Primary.ID), nil
+}
+
 func TestAccCloudStackNIC_update(t *testing.T) {
 	var nic cloudstack.Nic
 
@@ -100,7 +129,7 @@ func testAccCheckCloudStackNICExists(
 			return fmt.Errorf("No NIC ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		vm, _, err := cs.VirtualMachine.GetVirtualMachineByID(rsv.Primary.ID)
 
 		if err != nil {
@@ -147,7 +176,7 @@ func testAccCheckCloudStackNICIPAddress(
 }
 
 func testAccCheckCloudStackNICDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	// Deleting the instance automatically deletes any additional NICs
 	for _, rs := range s.RootModule().Resources {
@@ -23,12 +23,11 @@ import (
 	"fmt"
 	"log"
 	"strconv"
-	"strings"
 	"sync"
-	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
@@ -38,6 +37,9 @@ func resourceCloudStackPortForward() *schema.Resource {
 		Read:   resourceCloudStackPortForwardRead,
 		Update: resourceCloudStackPortForwardUpdate,
 		Delete: resourceCloudStackPortForwardDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackPortForwardImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"ip_address_id": {
@@ -64,18 +66,21 @@ func resourceCloudStackPortForward() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"protocol": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"tcp", "udp"}, false),
 						},
 
 						"private_port": {
-							Type:     schema.TypeInt,
-							Required: true,
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validatePort,
 						},
 
 						"public_port": {
-							Type:     schema.TypeInt,
-							Required: true,
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validatePort,
 						},
 
 						"virtual_machine_id": {
@@ -127,28 +132,26 @@ func createPortForwards(d *schema.ResourceData, meta interface{}, forwards *sche
 	var wg sync.WaitGroup
 	wg.Add(nrs.Len())
 
-	sem := make(chan struct{}, 10)
+	var mu sync.Mutex
 	for _, forward := range nrs.List() {
-		// Put in a tiny sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(forward map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Create a single forward
 			err := createPortForward(d, meta, forward)
 
 			// If we have a UUID, we need to save the forward
 			if forward["uuid"].(string) != "" {
+				mu.Lock()
 				forwards.Add(forward)
+				mu.Unlock()
 			}
 
 			if err != nil {
+				mu.Lock()
 				errs = multierror.Append(errs, err)
+				mu.Unlock()
 			}
-
-			<-sem
 		}(forward.(map[string]interface{}))
 	}
 
@@ -158,7 +161,7 @@ func createPortForwards(d *schema.ResourceData, meta interface{}, forwards *sche
 }
 
 func createPortForward(d *schema.ResourceData, meta interface{}, forward map[string]interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Make sure all required parameters are there
 	if err := verifyPortForwardParams(d, forward); err != nil {
@@ -214,7 +217,7 @@ func createPortForward(d *schema.ResourceData, meta interface{}, forward map[str
 }
 
 func resourceCloudStackPortForwardRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// First check if the IP address is still associated
 	_, count, err := cs.Address.GetPublicIpAddressByID(
@@ -222,7 +225,7 @@ func resourceCloudStackPortForwardRead(d *schema.ResourceData, meta interface{})
 		cloudstack.WithProject(d.Get("project").(string)),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf(
 				"[DEBUG] IP address with ID %s is no longer associated", d.Id())
 			d.SetId("")
@@ -390,34 +393,93 @@ func resourceCloudStackPortForwardDelete(d *schema.ResourceData, meta interface{
 	return nil
 }
 
+func resourceCloudStackPortForwardImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*providerMeta)
+
+	// Split off the optional project
+	if _, err := importStatePassthrough(d, meta); err != nil {
+		return nil, err
+	}
+
+	// Make sure the IP address exists
+	_, _, err := cs.Address.GetPublicIpAddressByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving IP address %s: %s", d.Id(), err)
+	}
+
+	// Get all the forwards from the running environment
+	p := cs.Firewall.NewListPortForwardingRulesParams()
+	p.SetIpaddressid(d.Id())
+	p.SetListall(true)
+
+	if err := setProjectid(p, cs, d); err != nil {
+		return nil, err
+	}
+
+	l, err := cs.Firewall.ListPortForwardingRules(p)
+	if err != nil {
+		return nil, err
+	}
+
+	forwards := resourceCloudStackPortForward().Schema["forward"].ZeroValue().(*schema.Set)
+	for _, f := range l.PortForwardingRules {
+		privPort, err := strconv.Atoi(f.Privateport)
+		if err != nil {
+			return nil, err
+		}
+
+		pubPort, err := strconv.Atoi(f.Publicport)
+		if err != nil {
+			return nil, err
+		}
+
+		forwards.Add(map[string]interface{}{
+			"protocol":           f.Protocol,
+			"private_port":       privPort,
+			"public_port":        pubPort,
+			"virtual_machine_id": f.Virtualmachineid,
+			"vm_guest_ip":        "",
+			"uuid":               f.Id,
+		})
+	}
+
+	// Imported port forwards manage all forwards of the IP address
+	d.Set("ip_address_id", d.Id())
+	d.Set("managed", true)
+	d.Set("forward", forwards)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func deletePortForwards(d *schema.ResourceData, meta interface{}, forwards *schema.Set, ors *schema.Set) error {
 	var errs *multierror.Error
 
 	var wg sync.WaitGroup
 	wg.Add(ors.Len())
 
-	sem := make(chan struct{}, 10)
+	var mu sync.Mutex
 	for _, forward := range ors.List() {
-		// Put a sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(forward map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Delete a single forward
 			err := deletePortForward(d, meta, forward)
 
 			// If we have a UUID, we need to save the forward
 			if forward["uuid"].(string) != "" {
+				mu.Lock()
 				forwards.Add(forward)
+				mu.Unlock()
 			}
 
 			if err != nil {
+				mu.Lock()
 				errs = multierror.Append(errs, err)
+				mu.Unlock()
 			}
-
-			<-sem
 		}(forward.(map[string]interface{}))
 	}
 
@@ -427,17 +489,14 @@ func deletePortForwards(d *schema.ResourceData, meta interface{}, forwards *sche
 }
 
 func deletePortForward(d *schema.ResourceData, meta interface{}, forward map[string]interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create the parameter struct
 	p := cs.Firewall.NewDeletePortForwardingRuleParams(forward["uuid"].(string))
 
 	// Delete the forward
 	if _, err := cs.Firewall.DeletePortForwardingRule(p); err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if !strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", forward["uuid"].(string))) {
+		if !isNotFound(err) {
 			return err
 		}
 	}
@@ -22,10 +22,8 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 func resourceCloudStackPrivateGateway() *schema.Resource {
@@ -40,21 +38,24 @@ func resourceCloudStackPrivateGateway() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"gateway": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPAddress,
 			},
 
 			"ip_address": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPAddress,
 			},
 
 			"netmask": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPAddress,
 			},
 
 			"vlan": {
@@ -90,7 +91,7 @@ func resourceCloudStackPrivateGateway() *schema.Resource {
 }
 
 func resourceCloudStackPrivateGatewayCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	ipaddress := d.Get("ip_address").(string)
 	networkofferingid := d.Get("network_offering").(string)
@@ -131,12 +132,12 @@ func resourceCloudStackPrivateGatewayCreate(d *schema.ResourceData, meta interfa
 }
 
 func resourceCloudStackPrivateGatewayRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the private gateway details
 	gw, count, err := cs.VPC.GetPrivateGatewayByID(d.Id())
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] Private gateway %s does no longer exist", d.Id())
 			d.SetId("")
 			return nil
@@ -156,7 +157,7 @@ func resourceCloudStackPrivateGatewayRead(d *schema.ResourceData, meta interface
 }
 
 func resourceCloudStackPrivateGatewayUpdate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Replace the ACL if the ID has changed
 	if d.HasChange("acl_id") {
@@ -173,18 +174,18 @@ func resourceCloudStackPrivateGatewayUpdate(d *schema.ResourceData, meta interfa
 }
 
 func resourceCloudStackPrivateGatewayDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.VPC.NewDeletePrivateGatewayParams(d.Id())
 
 	// Delete the private gateway
-	_, err := cs.VPC.DeletePrivateGateway(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.VPC.DeletePrivateGateway(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
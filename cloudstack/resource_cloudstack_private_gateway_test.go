@@ -79,7 +79,7 @@ func testAccCheckCloudStackPrivateGatewayExists(
 			return fmt.Errorf("No Private Gateway ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		pgw, _, err := cs.VPC.GetPrivateGatewayByID(rs.Primary.ID)
 
 		if err != nil {
@@ -117,7 +117,7 @@ func testAccCheckCloudStackPrivateGatewayAttributes(
 }
 
 func testAccCheckCloudStackPrivateGatewayDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_private_gateway" {
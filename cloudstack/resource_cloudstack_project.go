@@ -0,0 +1,257 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceCloudStackProject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackProjectCreate,
+		Read:   resourceCloudStackProjectRead,
+		Update: resourceCloudStackProjectUpdate,
+		Delete: resourceCloudStackProjectDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"display_text": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"account": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"accounts": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+		},
+	}
+}
+
+func resourceCloudStackProjectCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+	defer invalidateIDs(cs, "project")
+
+	name := d.Get("name").(string)
+
+	// Set the display text
+	displaytext, ok := d.GetOk("display_text")
+	if !ok {
+		displaytext = name
+	}
+
+	// Create a new parameter struct
+	p := cs.Project.NewCreateProjectParams(displaytext.(string), name)
+
+	// If there is a account supplied, make sure to add it to the request
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Creating project %s", name)
+	r, err := cs.Project.CreateProject(p)
+	if err != nil {
+		return fmt.Errorf("Error creating project %s: %s", name, err)
+	}
+
+	d.SetId(r.Id)
+
+	// Add the member accounts to the project
+	if accounts := d.Get("accounts").(*schema.Set); accounts.Len() > 0 {
+		if err := updateProjectAccounts(cs, d.Id(), &schema.Set{F: schema.HashString}, accounts); err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackProjectRead(d, meta)
+}
+
+func resourceCloudStackProjectRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	// Get the project details
+	p, count, err := cs.Project.GetProjectByID(d.Id(), withAccount(cs, d))
+	if err != nil {
+		if count == 0 || isNotFound(err) {
+			log.Printf("[DEBUG] Project %s does no longer exist", d.Get("name").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("name", p.Name)
+	d.Set("display_text", p.Displaytext)
+
+	owner, accounts, err := listProjectAccounts(cs, d.Id())
+	if err != nil {
+		return err
+	}
+
+	setAccountAndDomain(d, owner, p.Domain, p.Domainid)
+	d.Set("accounts", accounts)
+
+	return nil
+}
+
+func resourceCloudStackProjectUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+	defer invalidateIDs(cs, "project")
+
+	name := d.Get("name").(string)
+
+	// Check if the display text is changed
+	if d.HasChange("display_text") {
+		// Create a new parameter struct
+		p := cs.Project.NewUpdateProjectParams(d.Id())
+
+		// Set the display text
+		displaytext, ok := d.GetOk("display_text")
+		if !ok {
+			displaytext = name
+		}
+		p.SetDisplaytext(displaytext.(string))
+
+		// Update the project
+		_, err := cs.Project.UpdateProject(p)
+		if err != nil {
+			return fmt.Errorf("Error updating display text of project %s: %s", name, err)
+		}
+	}
+
+	// Check if the member accounts are changed
+	if d.HasChange("accounts") {
+		o, n := d.GetChange("accounts")
+		if err := updateProjectAccounts(cs, d.Id(), o.(*schema.Set), n.(*schema.Set)); err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackProjectRead(d, meta)
+}
+
+func resourceCloudStackProjectDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+	defer invalidateIDs(cs, "project")
+
+	// Create a new parameter struct
+	p := cs.Project.NewDeleteProjectParams(d.Id())
+
+	// Delete the project
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.Project.DeleteProject(p)
+		return err
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting project %s: %s", d.Get("name").(string), err)
+	}
+
+	return nil
+}
+
+// listProjectAccounts returns the owner and the other member accounts of a
+// project. The typed response of the client does not match the API, so the
+// accounts are requested using a custom request.
+func listProjectAccounts(cs *providerMeta, projectid string) (string, []string, error) {
+	p := &cloudstack.CustomServiceParams{}
+	p.SetParam("projectid", projectid)
+
+	var r struct {
+		Count           int `json:"count"`
+		ProjectAccounts []struct {
+			Account string `json:"account"`
+			Role    string `json:"role"`
+		} `json:"projectaccount"`
+	}
+
+	if err := cs.Custom.CustomRequest("listProjectAccounts", p, &r); err != nil {
+		return "", nil, fmt.Errorf("Error retrieving accounts of project %s: %s", projectid, err)
+	}
+
+	var owner string
+	var accounts []string
+	for _, a := range r.ProjectAccounts {
+		if a.Role == "Admin" && owner == "" {
+			owner = a.Account
+			continue
+		}
+		accounts = append(accounts, a.Account)
+	}
+
+	return owner, accounts, nil
+}
+
+// updateProjectAccounts adds and removes the member accounts of a project.
+func updateProjectAccounts(cs *providerMeta, projectid string, o, n *schema.Set) error {
+	for _, account := range o.Difference(n).List() {
+		p := cs.Account.NewDeleteAccountFromProjectParams(account.(string), projectid)
+
+		log.Printf("[DEBUG] Removing account %s from project %s", account, projectid)
+		if _, err := cs.Account.DeleteAccountFromProject(p); err != nil {
+			return fmt.Errorf("Error removing account %s from project %s: %s", account, projectid, err)
+		}
+	}
+
+	for _, account := range n.Difference(o).List() {
+		p := cs.Account.NewAddAccountToProjectParams(projectid)
+		p.SetAccount(account.(string))
+
+		log.Printf("[DEBUG] Adding account %s to project %s", account, projectid)
+		if _, err := cs.Account.AddAccountToProject(p); err != nil {
+			return fmt.Errorf("Error adding account %s to project %s: %s", account, projectid, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccCloudStackProject_basic(t *testing.T) {
+	var project cloudstack.Project
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackProject_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackProjectExists("cloudstack_project.foo", &project),
+					testAccCheckCloudStackProjectAttributes(&project),
+					resource.TestCheckResourceAttr(
+						"cloudstack_project.foo", "account", "tenant"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_project.foo", "domain", "ROOT/tenants"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_project.foo", "accounts.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackProject_accounts(t *testing.T) {
+	var project cloudstack.Project
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackProject_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackProjectExists("cloudstack_project.foo", &project),
+					testAccCheckCloudStackProjectAccounts("cloudstack_project.foo"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackProject_accounts,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackProjectExists("cloudstack_project.foo", &project),
+					testAccCheckCloudStackProjectAccounts("cloudstack_project.foo", "terraform-account"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_project.foo", "display_text", "terraform-project-updated"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackProject_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackProjectExists("cloudstack_project.foo", &project),
+					testAccCheckCloudStackProjectAccounts("cloudstack_project.foo"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackProject_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackProject_accounts,
+			},
+
+			{
+				ResourceName:            "cloudstack_project.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"domain"},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackProjectExists(
+	n string, project *cloudstack.Project) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No project ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*providerMeta)
+		p, _, err := cs.Project.GetProjectByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if p.Id != rs.Primary.ID {
+			return fmt.Errorf("Project not found")
+		}
+
+		*project = *p
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackProjectAttributes(
+	project *cloudstack.Project) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if project.Name != "terraform-project" {
+			return fmt.Errorf("Bad name: %s", project.Name)
+		}
+
+		if project.Displaytext != "terraform-project" {
+			return fmt.Errorf("Bad display text: %s", project.Displaytext)
+		}
+
+		if project.Domain != "tenants" {
+			return fmt.Errorf("Bad domain: %s", project.Domain)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackProjectAccounts(n string, want ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		cs := testAccProvider.Meta().(*providerMeta)
+		owner, accounts, err := listProjectAccounts(cs, rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if owner != "tenant" {
+			return fmt.Errorf("Bad owner: %s", owner)
+		}
+
+		if fmt.Sprint(accounts) != fmt.Sprint(want) {
+			return fmt.Errorf("Bad accounts: %v, expected: %v", accounts, want)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackProjectDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*providerMeta)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_project" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No project ID is set")
+		}
+
+		_, _, err := cs.Project.GetProjectByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Project %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackProject_basic = `
+resource "cloudstack_account" "foo" {
+  name       = "terraform-account"
+  domain     = "ROOT/tenants"
+  username   = "terraform-user"
+  password   = "s3cr3t"
+  email      = "terraform@example.com"
+  first_name = "Terraform"
+  last_name  = "User"
+}
+
+resource "cloudstack_project" "foo" {
+  name    = "terraform-project"
+  account = "tenant"
+  domain  = "ROOT/tenants"
+}`
+
+const testAccCloudStackProject_accounts = `
+resource "cloudstack_account" "foo" {
+  name       = "terraform-account"
+  domain     = "ROOT/tenants"
+  username   = "terraform-user"
+  password   = "s3cr3t"
+  email      = "terraform@example.com"
+  first_name = "Terraform"
+  last_name  = "User"
+}
+
+resource "cloudstack_project" "foo" {
+  name         = "terraform-project"
+  display_text = "terraform-project-updated"
+  account      = "tenant"
+  domain       = "ROOT/tenants"
+  accounts     = ["${cloudstack_account.foo.name}"]
+}`
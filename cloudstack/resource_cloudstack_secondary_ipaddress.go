@@ -22,10 +22,8 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 func resourceCloudStackSecondaryIPAddress() *schema.Resource {
@@ -33,13 +31,17 @@ func resourceCloudStackSecondaryIPAddress() *schema.Resource {
 		Create: resourceCloudStackSecondaryIPAddressCreate,
 		Read:   resourceCloudStackSecondaryIPAddressRead,
 		Delete: resourceCloudStackSecondaryIPAddressDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackSecondaryIPAddressImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"ip_address": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPAddress,
 			},
 
 			"nic_id": {
@@ -59,7 +61,7 @@ func resourceCloudStackSecondaryIPAddress() *schema.Resource {
 }
 
 func resourceCloudStackSecondaryIPAddressCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	nicid, ok := d.GetOk("nic_id")
 	if !ok {
@@ -68,7 +70,7 @@ func resourceCloudStackSecondaryIPAddressCreate(d *schema.ResourceData, meta int
 		// Get the virtual machine details
 		vm, count, err := cs.VirtualMachine.GetVirtualMachineByID(virtualmachineid)
 		if err != nil {
-			if count == 0 {
+			if count == 0 || isNotFound(err) {
 				log.Printf("[DEBUG] Virtual Machine %s does no longer exist", virtualmachineid)
 				d.SetId("")
 				return nil
@@ -98,14 +100,14 @@ func resourceCloudStackSecondaryIPAddressCreate(d *schema.ResourceData, meta int
 }
 
 func resourceCloudStackSecondaryIPAddressRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	virtualmachineid := d.Get("virtual_machine_id").(string)
 
 	// Get the virtual machine details
 	vm, count, err := cs.VirtualMachine.GetVirtualMachineByID(virtualmachineid)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] Virtual Machine %s does no longer exist", virtualmachineid)
 			d.SetId("")
 			return nil
@@ -152,17 +154,18 @@ func resourceCloudStackSecondaryIPAddressRead(d *schema.ResourceData, meta inter
 }
 
 func resourceCloudStackSecondaryIPAddressDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.Nic.NewRemoveIpFromNicParams(d.Id())
 
 	log.Printf("[INFO] Removing secondary IP address: %s", d.Get("ip_address").(string))
-	if _, err := cs.Nic.RemoveIpFromNic(p); err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.Nic.RemoveIpFromNic(p)
+		return err
+	})
+	if err != nil {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -171,3 +174,35 @@ func resourceCloudStackSecondaryIPAddressDelete(d *schema.ResourceData, meta int
 
 	return nil
 }
+
+func resourceCloudStackSecondaryIPAddressImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*providerMeta)
+
+	// A secondary IP address can only be found through the virtual machine
+	// it belongs to
+	virtualmachineid, id, err := splitImportID(d.Id(), "<VIRTUAL MACHINE ID>/<SECONDARY IP ADDRESS ID>")
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := cs.Nic.ListNics(cs.Nic.NewListNicsParams(virtualmachineid))
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving the NICs of virtual machine %s: %s", virtualmachineid, err)
+	}
+
+	// Find the NIC the IP address is added to
+	for _, nic := range l.Nics {
+		for _, ip := range nic.Secondaryip {
+			if ip.Id == id {
+				d.Set("nic_id", nic.Id)
+				d.Set("virtual_machine_id", virtualmachineid)
+				d.SetId(id)
+
+				return []*schema.ResourceData{d}, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"Secondary IP address %s not found on virtual machine %s", id, virtualmachineid)
+}
@@ -47,6 +47,35 @@ func TestAccCloudStackSecondaryIPAddress_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackSecondaryIPAddress_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSecondaryIPAddressDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSecondaryIPAddress_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_secondary_ipaddress.foo",
+				ImportState:       true,
+				ImportStateIdFunc: testAccCloudStackSecondaryIPAddressImportStateID,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCloudStackSecondaryIPAddressImportStateID(s *terraform.State) (string, error) {
+	rs, ok := s.RootModule().Resources["cloudstack_secondary_ipaddress.foo"]
+	if !ok {
+		return "", fmt.Errorf("Not found: cloudstack_secondary_ipaddress.foo")
+	}
+
+	return fmt.Sprintf("%s/%s", rs.Primary.Attributes["virtual_machine_id"], rs.Primary.ID), nil
+}
+
 func TestAccCloudStackSecondaryIPAddress_fixedIP(t *testing.T) {
 	var ip cloudstack.AddIpToNicResponse
 
@@ -81,7 +110,7 @@ func testAccCheckCloudStackSecondaryIPAddressExists(
 			return fmt.Errorf("No IP address ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 
 		virtualmachine, ok := rs.Primary.Attributes["virtual_machine_id"]
 		if !ok {
@@ -151,7 +180,7 @@ func testAccCheckCloudStackSecondaryIPAddressAttributes(
 }
 
 func testAccCheckCloudStackSecondaryIPAddressDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_secondary_ipaddress" {
@@ -22,7 +22,6 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -57,12 +56,27 @@ func resourceCloudStackSecurityGroup() *schema.Resource {
 				Computed: true,
 				ForceNew: true,
 			},
+
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
 		},
 	}
 }
 
 func resourceCloudStackSecurityGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	name := d.Get("name").(string)
 
@@ -81,6 +95,11 @@ func resourceCloudStackSecurityGroupCreate(d *schema.ResourceData, meta interfac
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	r, err := cs.SecurityGroup.CreateSecurityGroup(p)
 	if err != nil {
 		return fmt.Errorf("Error creating security group %s: %s", name, err)
@@ -92,15 +111,16 @@ func resourceCloudStackSecurityGroupCreate(d *schema.ResourceData, meta interfac
 }
 
 func resourceCloudStackSecurityGroupRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the security group details
 	sg, count, err := cs.SecurityGroup.GetSecurityGroupByID(
 		d.Id(),
 		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] Security group %s does not longer exist", d.Get("name").(string))
 			d.SetId("")
 			return nil
@@ -114,12 +134,13 @@ func resourceCloudStackSecurityGroupRead(d *schema.ResourceData, meta interface{
 	d.Set("description", sg.Description)
 
 	setValueOrID(d, "project", sg.Project, sg.Projectid)
+	setAccountAndDomain(d, sg.Account, sg.Domain, sg.Domainid)
 
 	return nil
 }
 
 func resourceCloudStackSecurityGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.SecurityGroup.NewDeleteSecurityGroupParams()
@@ -130,13 +151,18 @@ func resourceCloudStackSecurityGroupDelete(d *schema.ResourceData, meta interfac
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// Delete the security group
-	_, err := cs.SecurityGroup.DeleteSecurityGroup(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.SecurityGroup.DeleteSecurityGroup(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -22,13 +22,14 @@ package cloudstack
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
@@ -49,6 +50,9 @@ func resourceCloudStackSecurityGroupRule() *schema.Resource {
 		Read:   resourceCloudStackSecurityGroupRuleRead,
 		Update: resourceCloudStackSecurityGroupRuleUpdate,
 		Delete: resourceCloudStackSecurityGroupRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCloudStackSecurityGroupRuleImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"security_group_id": {
@@ -65,38 +69,48 @@ func resourceCloudStackSecurityGroupRule() *schema.Resource {
 						"cidr_list": {
 							Type:     schema.TypeSet,
 							Optional: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-							Set:      schema.HashString,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateCIDR,
+							},
+							Set: schema.HashString,
 						},
 
 						"protocol": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateProtocol("tcp", "udp", "icmp"),
 						},
 
 						"icmp_type": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateICMP,
 						},
 
 						"icmp_code": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateICMP,
 						},
 
 						"ports": {
 							Type:     schema.TypeSet,
 							Optional: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-							Set:      schema.HashString,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validatePortRange,
+							},
+							Set: schema.HashString,
 						},
 
 						"traffic_type": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "ingress",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "ingress",
+							ValidateFunc: validation.StringInSlice(trafficTypes, false),
 						},
 
 						"user_security_group_list": {
@@ -121,9 +135,10 @@ func resourceCloudStackSecurityGroupRule() *schema.Resource {
 			},
 
 			"parallelism": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  2,
+				Type:       schema.TypeInt,
+				Optional:   true,
+				Default:    2,
+				Deprecated: "API calls are now limited by the provider wide 'max_requests_per_second' and 'max_concurrent_requests' arguments",
 			},
 		},
 	}
@@ -152,24 +167,22 @@ func resourceCloudStackSecurityGroupRuleCreate(d *schema.ResourceData, meta inte
 }
 
 func createSecurityGroupRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, nrs *schema.Set) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 	var errs *multierror.Error
 
 	var wg sync.WaitGroup
 	wg.Add(nrs.Len())
 
-	sem := make(chan struct{}, d.Get("parallelism").(int))
+	var mu sync.Mutex
 	for _, rule := range nrs.List() {
-		// Put in a tiny sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Make sure all required parameters are there
 			if err := verifySecurityGroupRuleParams(d, rule); err != nil {
+				mu.Lock()
 				errs = multierror.Append(errs, err)
+				mu.Unlock()
 				return
 			}
 
@@ -191,7 +204,9 @@ func createSecurityGroupRules(d *schema.ResourceData, meta interface{}, rules *s
 					// Create a single rule
 					err := createSecurityGroupRule(d, meta, rule, p, cidr.(string))
 					if err != nil {
+						mu.Lock()
 						errs = multierror.Append(errs, err)
+						mu.Unlock()
 					}
 				}
 			}
@@ -203,7 +218,9 @@ func createSecurityGroupRules(d *schema.ResourceData, meta interface{}, rules *s
 						cloudstack.WithProject(d.Get("project").(string)),
 					)
 					if err != nil {
+						mu.Lock()
 						errs = multierror.Append(errs, err)
+						mu.Unlock()
 						continue
 					}
 
@@ -221,17 +238,19 @@ func createSecurityGroupRules(d *schema.ResourceData, meta interface{}, rules *s
 					// Create a single rule
 					err = createSecurityGroupRule(d, meta, rule, p, usg.(string))
 					if err != nil {
+						mu.Lock()
 						errs = multierror.Append(errs, err)
+						mu.Unlock()
 					}
 				}
 			}
 
 			// If we have at least one UUID, we need to save the rule
 			if len(rule["uuids"].(map[string]interface{})) > 0 {
+				mu.Lock()
 				rules.Add(rule)
+				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -241,7 +260,7 @@ func createSecurityGroupRules(d *schema.ResourceData, meta interface{}, rules *s
 }
 
 func createSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}, p authorizeSecurityGroupParams, uuid string) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 	uuids := rule["uuids"].(map[string]interface{})
 
 	// Set the protocol
@@ -276,6 +295,10 @@ func createSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[
 				}
 
 				m := splitPorts.FindStringSubmatch(port.(string))
+				if m == nil {
+					return fmt.Errorf(
+						"%q is not a valid port value. Valid options are '80' or '80-90'", port.(string))
+				}
 
 				startPort, err := strconv.Atoi(m[1])
 				if err != nil {
@@ -310,7 +333,7 @@ func createSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[
 	return nil
 }
 
-func createIngressOrEgressRule(cs *cloudstack.CloudStackClient, p authorizeSecurityGroupParams) (string, error) {
+func createIngressOrEgressRule(cs *providerMeta, p authorizeSecurityGroupParams) (string, error) {
 	switch p := p.(type) {
 	case *cloudstack.AuthorizeSecurityGroupIngressParams:
 		r, err := cs.SecurityGroup.AuthorizeSecurityGroupIngress(p)
@@ -330,7 +353,7 @@ func createIngressOrEgressRule(cs *cloudstack.CloudStackClient, p authorizeSecur
 }
 
 func resourceCloudStackSecurityGroupRuleRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the security group details
 	sg, count, err := cs.SecurityGroup.GetSecurityGroupByID(
@@ -338,7 +361,7 @@ func resourceCloudStackSecurityGroupRuleRead(d *schema.ResourceData, meta interf
 		cloudstack.WithProject(d.Get("project").(string)),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] Security group %s does not longer exist", d.Id())
 			d.SetId("")
 			return nil
@@ -525,33 +548,168 @@ func resourceCloudStackSecurityGroupRuleDelete(d *schema.ResourceData, meta inte
 	return nil
 }
 
+func resourceCloudStackSecurityGroupRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cs := meta.(*providerMeta)
+
+	// Split off the optional project
+	if _, err := importStatePassthrough(d, meta); err != nil {
+		return nil, err
+	}
+
+	// Get the security group details
+	sg, _, err := cs.SecurityGroup.GetSecurityGroupByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Error retrieving security group %s: %s", d.Id(), err)
+	}
+
+	// Group the rules back into the rules they were created from
+	rules := resourceCloudStackSecurityGroupRule().Schema["rule"].ZeroValue().(*schema.Set)
+	for _, rule := range groupSecurityGroupRules("ingress", sg.Ingressrule) {
+		rules.Add(rule)
+	}
+	for _, rule := range groupSecurityGroupRules("egress", sg.Egressrule) {
+		rules.Add(rule)
+	}
+
+	d.Set("security_group_id", d.Id())
+	d.Set("rule", rules)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// groupSecurityGroupRules groups the rules of a security group into the rules
+// they were created from. A rule is created for every combination of a CIDR
+// or security group and a port, so CIDRs and security groups that allow the
+// same protocol and ports are grouped into a single rule.
+func groupSecurityGroupRules(trafficType string, sgRules []cloudstack.SecurityGroupRule) []map[string]interface{} {
+	type source struct {
+		name     string
+		isCIDR   bool
+		protocol string
+		icmpType int
+		icmpCode int
+		ports    []string
+		uuids    map[string]interface{}
+	}
+
+	// First collect the ports of every source, per protocol
+	var keys []string
+	sources := make(map[string][]*source)
+	for _, r := range sgRules {
+		name, isCIDR := r.Cidr, true
+		if name == "" {
+			name, isCIDR = r.Securitygroupname, false
+		}
+
+		key, port := r.Protocol, "icmp"
+		if r.Protocol == "icmp" {
+			key = fmt.Sprintf("icmp/%d/%d", r.Icmptype, r.Icmpcode)
+		} else {
+			port = portRange(r.Startport, r.Endport)
+		}
+
+		if _, ok := sources[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		var src *source
+		for _, s := range sources[key] {
+			if s.name == name && s.isCIDR == isCIDR {
+				src = s
+				break
+			}
+		}
+		if src == nil {
+			src = &source{
+				name:     name,
+				isCIDR:   isCIDR,
+				protocol: r.Protocol,
+				icmpType: r.Icmptype,
+				icmpCode: r.Icmpcode,
+				uuids:    make(map[string]interface{}),
+			}
+			sources[key] = append(sources[key], src)
+		}
+
+		src.ports = append(src.ports, port)
+		src.uuids[name+port] = r.Ruleid
+	}
+
+	// Then group the sources with the same ports into a single rule
+	var rules []map[string]interface{}
+	for _, key := range keys {
+		grouped := make(map[string]map[string]interface{})
+
+		for _, src := range sources[key] {
+			ports := append([]string(nil), src.ports...)
+			sort.Strings(ports)
+			portKey := strings.Join(ports, ",")
+
+			rule, ok := grouped[portKey]
+			if !ok {
+				portSet := &schema.Set{F: schema.HashString}
+				if src.protocol != "icmp" {
+					for _, port := range ports {
+						portSet.Add(port)
+					}
+				}
+
+				rule = map[string]interface{}{
+					"cidr_list":                &schema.Set{F: schema.HashString},
+					"user_security_group_list": &schema.Set{F: schema.HashString},
+					"protocol":                 src.protocol,
+					"icmp_type":                src.icmpType,
+					"icmp_code":                src.icmpCode,
+					"ports":                    portSet,
+					"traffic_type":             trafficType,
+					"uuids":                    make(map[string]interface{}),
+				}
+				grouped[portKey] = rule
+				rules = append(rules, rule)
+			}
+
+			if src.isCIDR {
+				rule["cidr_list"].(*schema.Set).Add(src.name)
+			} else {
+				rule["user_security_group_list"].(*schema.Set).Add(src.name)
+			}
+			for k, v := range src.uuids {
+				rule["uuids"].(map[string]interface{})[k] = v
+			}
+		}
+	}
+
+	return rules
+}
+
 func deleteSecurityGroupRules(d *schema.ResourceData, meta interface{}, rules *schema.Set, ors *schema.Set) error {
 	var errs *multierror.Error
 
 	var wg sync.WaitGroup
 	wg.Add(ors.Len())
 
-	sem := make(chan struct{}, d.Get("parallelism").(int))
+	var mu sync.Mutex
 	for _, rule := range ors.List() {
-		// Put a sleep here to avoid DoS'ing the API
-		time.Sleep(500 * time.Millisecond)
-
 		go func(rule map[string]interface{}) {
 			defer wg.Done()
-			sem <- struct{}{}
 
 			// Create a single rule
 			err := deleteSecurityGroupRule(d, meta, rule)
 			if err != nil {
+				mu.Lock()
 				errs = multierror.Append(errs, err)
+				mu.Unlock()
 			}
 
 			// If we have at least one UUID, we need to save the rule
 			if len(rule["uuids"].(map[string]interface{})) > 0 {
+				mu.Lock()
 				rules.Add(rule)
+				mu.Unlock()
 			}
-
-			<-sem
 		}(rule.(map[string]interface{}))
 	}
 
@@ -561,7 +719,7 @@ func deleteSecurityGroupRules(d *schema.ResourceData, meta interface{}, rules *s
 }
 
 func deleteSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[string]interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 	uuids := rule["uuids"].(map[string]interface{})
 
 	for k, id := range uuids {
@@ -581,10 +739,7 @@ func deleteSecurityGroupRule(d *schema.ResourceData, meta interface{}, rule map[
 		}
 
 		if err != nil {
-			// This is a very poor way to be told the ID does no longer exist :(
-			if strings.Contains(err.Error(), fmt.Sprintf(
-				"Invalid parameter id value=%s due to incorrect long value format, "+
-					"or entity does not exist", id.(string))) {
+			if isNotFound(err) {
 				delete(uuids, k)
 				continue
 			}
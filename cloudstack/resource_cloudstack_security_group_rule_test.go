@@ -26,7 +26,6 @@ import (
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 func TestAccCloudStackSecurityGroupRule_basic(t *testing.T) {
@@ -67,6 +66,26 @@ func TestAccCloudStackSecurityGroupRule_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackSecurityGroupRule_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSecurityGroupRuleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSecurityGroupRule_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_security_group_rule.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"parallelism"},
+			},
+		},
+	})
+}
+
 func TestAccCloudStackSecurityGroupRule_update(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -153,7 +172,7 @@ func testAccCheckCloudStackSecurityGroupRulesExist(n string) resource.TestCheckF
 			return fmt.Errorf("No security group rule ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		sg, count, err := cs.SecurityGroup.GetSecurityGroupByID(rs.Primary.ID)
 		if err != nil {
 			if count == 0 {
@@ -184,7 +203,7 @@ func testAccCheckCloudStackSecurityGroupRulesExist(n string) resource.TestCheckF
 }
 
 func testAccCheckCloudStackSecurityGroupRuleDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_security_group_rule" {
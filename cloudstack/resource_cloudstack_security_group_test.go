@@ -78,7 +78,7 @@ func testAccCheckCloudStackSecurityGroupExists(
 			return fmt.Errorf("No security group ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		resp, _, err := cs.SecurityGroup.GetSecurityGroupByID(rs.Primary.ID)
 		if err != nil {
 			return err
@@ -111,7 +111,7 @@ func testAccCheckCloudStackSecurityGroupBasicAttributes(
 }
 
 func testAccCheckCloudStackSecurityGroupDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_security_group" {
@@ -22,10 +22,8 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 func resourceCloudStackSSHKeyPair() *schema.Resource {
@@ -33,6 +31,9 @@ func resourceCloudStackSSHKeyPair() *schema.Resource {
 		Create: resourceCloudStackSSHKeyPairCreate,
 		Read:   resourceCloudStackSSHKeyPairRead,
 		Delete: resourceCloudStackSSHKeyPairDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -53,6 +54,21 @@ func resourceCloudStackSSHKeyPair() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
 			"private_key": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -67,7 +83,7 @@ func resourceCloudStackSSHKeyPair() *schema.Resource {
 }
 
 func resourceCloudStackSSHKeyPairCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	name := d.Get("name").(string)
 	publicKey := d.Get("public_key").(string)
@@ -81,6 +97,11 @@ func resourceCloudStackSSHKeyPairCreate(d *schema.ResourceData, meta interface{}
 			return err
 		}
 
+		// If there is an account supplied, we retrieve and set the account and domain id
+		if err := setAccountid(p, cs, d); err != nil {
+			return err
+		}
+
 		_, err := cs.SSH.RegisterSSHKeyPair(p)
 		if err != nil {
 			return err
@@ -94,6 +115,11 @@ func resourceCloudStackSSHKeyPairCreate(d *schema.ResourceData, meta interface{}
 			return err
 		}
 
+		// If there is an account supplied, we retrieve and set the account and domain id
+		if err := setAccountid(p, cs, d); err != nil {
+			return err
+		}
+
 		r, err := cs.SSH.CreateSSHKeyPair(p)
 		if err != nil {
 			return err
@@ -108,7 +134,7 @@ func resourceCloudStackSSHKeyPairCreate(d *schema.ResourceData, meta interface{}
 }
 
 func resourceCloudStackSSHKeyPairRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	log.Printf("[DEBUG] looking for key pair with name %s", d.Id())
 
@@ -120,6 +146,11 @@ func resourceCloudStackSSHKeyPairRead(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	r, err := cs.SSH.ListSSHKeyPairs(p)
 	if err != nil {
 		return err
@@ -134,11 +165,13 @@ func resourceCloudStackSSHKeyPairRead(d *schema.ResourceData, meta interface{})
 	d.Set("name", r.SSHKeyPairs[0].Name)
 	d.Set("fingerprint", r.SSHKeyPairs[0].Fingerprint)
 
+	setAccountAndDomain(d, r.SSHKeyPairs[0].Account, r.SSHKeyPairs[0].Domain, r.SSHKeyPairs[0].Domainid)
+
 	return nil
 }
 
 func resourceCloudStackSSHKeyPairDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.SSH.NewDeleteSSHKeyPairParams(d.Id())
@@ -148,12 +181,18 @@ func resourceCloudStackSSHKeyPairDelete(d *schema.ResourceData, meta interface{}
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// Remove the SSH Keypair
-	_, err := cs.SSH.DeleteSSHKeyPair(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.SSH.DeleteSSHKeyPair(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"A key pair with name '%s' does not exist for account", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
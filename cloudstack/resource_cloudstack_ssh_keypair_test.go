@@ -70,6 +70,26 @@ func TestAccCloudStackSSHKeyPair_register(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackSSHKeyPair_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackSSHKeyPairDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackSSHKeyPair_create,
+			},
+
+			{
+				ResourceName:            "cloudstack_ssh_keypair.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"private_key"},
+			},
+		},
+	})
+}
+
 func testAccCheckCloudStackSSHKeyPairExists(n string, sshkey *cloudstack.SSHKeyPair) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -81,7 +101,7 @@ func testAccCheckCloudStackSSHKeyPairExists(n string, sshkey *cloudstack.SSHKeyP
 			return fmt.Errorf("No key pair ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		p := cs.SSH.NewListSSHKeyPairsParams()
 		p.SetName(rs.Primary.ID)
 
@@ -145,7 +165,7 @@ func testAccCheckCloudStackSSHKeyPairCreateAttributes(name string) resource.Test
 }
 
 func testAccCheckCloudStackSSHKeyPairDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_ssh_keypair" {
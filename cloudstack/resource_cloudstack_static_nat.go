@@ -22,7 +22,6 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -34,6 +33,9 @@ func resourceCloudStackStaticNAT() *schema.Resource {
 		Exists: resourceCloudStackStaticNATExists,
 		Read:   resourceCloudStackStaticNATRead,
 		Delete: resourceCloudStackStaticNATDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"ip_address_id": {
@@ -66,7 +68,7 @@ func resourceCloudStackStaticNAT() *schema.Resource {
 }
 
 func resourceCloudStackStaticNATCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	ipaddressid := d.Get("ip_address_id").(string)
 
@@ -115,7 +117,7 @@ func resourceCloudStackStaticNATCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceCloudStackStaticNATExists(d *schema.ResourceData, meta interface{}) (bool, error) {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the IP address details
 	ip, count, err := cs.Address.GetPublicIpAddressByID(
@@ -123,7 +125,7 @@ func resourceCloudStackStaticNATExists(d *schema.ResourceData, meta interface{})
 		cloudstack.WithProject(d.Get("project").(string)),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] IP address with ID %s no longer exists", d.Id())
 			return false, nil
 		}
@@ -135,7 +137,7 @@ func resourceCloudStackStaticNATExists(d *schema.ResourceData, meta interface{})
 }
 
 func resourceCloudStackStaticNATRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the IP address details
 	ip, count, err := cs.Address.GetPublicIpAddressByID(
@@ -143,7 +145,7 @@ func resourceCloudStackStaticNATRead(d *schema.ResourceData, meta interface{}) e
 		cloudstack.WithProject(d.Get("project").(string)),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] IP address with ID %s no longer exists", d.Id())
 			d.SetId("")
 			return nil
@@ -158,6 +160,7 @@ func resourceCloudStackStaticNATRead(d *schema.ResourceData, meta interface{}) e
 		return nil
 	}
 
+	d.Set("ip_address_id", ip.Id)
 	d.Set("virtual_machine_id", ip.Virtualmachineid)
 	d.Set("vm_guest_ip", ip.Vmipaddress)
 
@@ -167,18 +170,18 @@ func resourceCloudStackStaticNATRead(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceCloudStackStaticNATDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.NAT.NewDisableStaticNatParams(d.Id())
 
 	// Disable static NAT
-	_, err := cs.NAT.DisableStaticNat(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.NAT.DisableStaticNat(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
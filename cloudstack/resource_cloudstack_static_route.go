@@ -22,10 +22,8 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 func resourceCloudStackStaticRoute() *schema.Resource {
@@ -33,12 +31,16 @@ func resourceCloudStackStaticRoute() *schema.Resource {
 		Create: resourceCloudStackStaticRouteCreate,
 		Read:   resourceCloudStackStaticRouteRead,
 		Delete: resourceCloudStackStaticRouteDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"cidr": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateCIDR,
 			},
 
 			"gateway_id": {
@@ -51,7 +53,7 @@ func resourceCloudStackStaticRoute() *schema.Resource {
 }
 
 func resourceCloudStackStaticRouteCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.VPC.NewCreateStaticRouteParams(
@@ -71,12 +73,12 @@ func resourceCloudStackStaticRouteCreate(d *schema.ResourceData, meta interface{
 }
 
 func resourceCloudStackStaticRouteRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the virtual machine details
 	r, count, err := cs.VPC.GetStaticRouteByID(d.Id())
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] Static route %s does no longer exist", d.Id())
 			d.SetId("")
 			return nil
@@ -86,23 +88,24 @@ func resourceCloudStackStaticRouteRead(d *schema.ResourceData, meta interface{})
 	}
 
 	d.Set("cidr", r.Cidr)
+	d.Set("gateway_id", r.Gatewayid)
 
 	return nil
 }
 
 func resourceCloudStackStaticRouteDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Create a new parameter struct
 	p := cs.VPC.NewDeleteStaticRouteParams(d.Id())
 
 	// Delete the private gateway
-	_, err := cs.VPC.DeleteStaticRoute(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.VPC.DeleteStaticRoute(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -48,6 +48,25 @@ func TestAccCloudStackStaticRoute_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackStaticRoute_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackStaticRouteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackStaticRoute_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_static_route.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccCheckCloudStackStaticRouteExists(
 	n string, staticroute *cloudstack.StaticRoute) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
@@ -60,7 +79,7 @@ func testAccCheckCloudStackStaticRouteExists(
 			return fmt.Errorf("No Static Route ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		route, _, err := cs.VPC.GetStaticRouteByID(rs.Primary.ID)
 
 		if err != nil {
@@ -90,7 +109,7 @@ func testAccCheckCloudStackStaticRouteAttributes(
 }
 
 func testAccCheckCloudStackStaticRouteDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_static_route" {
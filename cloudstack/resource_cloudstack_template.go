@@ -22,10 +22,10 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
@@ -35,6 +35,17 @@ func resourceCloudStackTemplate() *schema.Resource {
 		Read:   resourceCloudStackTemplateRead,
 		Update: resourceCloudStackTemplateUpdate,
 		Delete: resourceCloudStackTemplateDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		CustomizeDiff: validateReferences("os_type", "project", "zone"),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: providerTimeout(),
+			Update: providerTimeout(),
+			Delete: providerTimeout(),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -68,6 +79,11 @@ func resourceCloudStackTemplate() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
+				// The URL is not returned by every CloudStack version, so
+				// it is unknown after importing a template
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return old == "" && d.Id() != ""
+				},
 			},
 
 			"project": {
@@ -77,6 +93,21 @@ func resourceCloudStackTemplate() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
 			"zone": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -121,9 +152,11 @@ func resourceCloudStackTemplate() *schema.Resource {
 			},
 
 			"is_ready_timeout": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  300,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      300,
+				Deprecated:   "Use the create timeout of the timeouts block instead, the longer of both is used",
+				ValidateFunc: validation.IntAtLeast(0),
 			},
 
 			"tags": tagsSchema(),
@@ -132,7 +165,8 @@ func resourceCloudStackTemplate() *schema.Resource {
 }
 
 func resourceCloudStackTemplateCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutCreate))
+	defer invalidateIDs(cs, "template")
 
 	if err := verifyTemplateParams(d); err != nil {
 		return err
@@ -196,6 +230,11 @@ func resourceCloudStackTemplateCreate(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// Create the new template
 	r, err := cs.Template.RegisterTemplate(p)
 	if err != nil {
@@ -209,9 +248,13 @@ func resourceCloudStackTemplateCreate(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Error setting tags on the template %s: %s", name, err)
 	}
 
-	// Wait until the template is ready to use, or timeout with an error...
+	// Wait until the template is ready to use, or timeout with an error. The
+	// deprecated is_ready_timeout is still used when it is the longer one
 	currentTime := time.Now().Unix()
-	timeout := int64(d.Get("is_ready_timeout").(int))
+	timeout := int64(d.Timeout(schema.TimeoutCreate).Seconds())
+	if t := int64(d.Get("is_ready_timeout").(int)); t > timeout {
+		timeout = t
+	}
 	for {
 		// Start with the sleep so the register action has a few seconds
 		// to process the registration correctly. Without this wait
@@ -233,16 +276,17 @@ func resourceCloudStackTemplateCreate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceCloudStackTemplateRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the template details
 	t, count, err := cs.Template.GetTemplateByID(
 		d.Id(),
 		"executable",
 		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf(
 				"[DEBUG] Template %s no longer exists", d.Get("name").(string))
 			d.SetId("")
@@ -263,6 +307,12 @@ func resourceCloudStackTemplateRead(d *schema.ResourceData, meta interface{}) er
 	d.Set("password_enabled", t.Passwordenabled)
 	d.Set("is_ready", t.Isready)
 
+	// Only set the URL when importing a template, as the URL is not returned
+	// by every CloudStack version
+	if _, ok := d.GetOk("url"); !ok {
+		d.Set("url", t.Url)
+	}
+
 	tags := make(map[string]interface{})
 	for _, tag := range t.Tags {
 		tags[tag.Key] = tag.Value
@@ -271,13 +321,16 @@ func resourceCloudStackTemplateRead(d *schema.ResourceData, meta interface{}) er
 
 	setValueOrID(d, "os_type", t.Ostypename, t.Ostypeid)
 	setValueOrID(d, "project", t.Project, t.Projectid)
+	setAccountAndDomain(d, t.Account, t.Domain, t.Domainid)
 	setValueOrID(d, "zone", t.Zonename, t.Zoneid)
 
 	return nil
 }
 
 func resourceCloudStackTemplateUpdate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutUpdate))
+	defer invalidateIDs(cs, "template")
+
 	name := d.Get("name").(string)
 
 	// Create a new parameter struct
@@ -326,19 +379,20 @@ func resourceCloudStackTemplateUpdate(d *schema.ResourceData, meta interface{})
 }
 
 func resourceCloudStackTemplateDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutDelete))
+	defer invalidateIDs(cs, "template")
 
 	// Create a new parameter struct
 	p := cs.Template.NewDeleteTemplateParams(d.Id())
 
 	// Delete the template
 	log.Printf("[INFO] Deleting template: %s", d.Get("name").(string))
-	_, err := cs.Template.DeleteTemplate(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.Template.DeleteTemplate(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -88,6 +89,87 @@ func TestAccCloudStackTemplate_update(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackTemplate_import(t *testing.T) {
+	if cloudStackTemplateURL == "" {
+		t.Skip("This test requires an upload URL")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackTemplateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackTemplate_basic,
+			},
+
+			{
+				ResourceName:            "cloudstack_template.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"is_ready_timeout"},
+			},
+		},
+	})
+}
+
+func TestAccCloudStackTemplate_importWithoutURL(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackTemplateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackTemplate_withoutURL,
+			},
+
+			{
+				ResourceName:            "cloudstack_template.foo",
+				ImportState:             true,
+				ImportStateCheck:        testAccCheckCloudStackTemplateImportedWithoutDiff,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"is_ready_timeout", "url"},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackTemplateImportedWithoutDiff(s []*terraform.InstanceState) error {
+	if len(s) != 1 {
+		return fmt.Errorf("Expected one imported template, got %d", len(s))
+	}
+
+	if s[0].Attributes["url"] != "" {
+		return fmt.Errorf("Expected the simulator not to return the URL, got %s", s[0].Attributes["url"])
+	}
+
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"name":       "terraform-test",
+		"format":     "QCOW2",
+		"hypervisor": "Simulator",
+		"os_type":    "CentOS 5.6 (64-bit)",
+		"url":        "http://example.com/terraform-test.qcow2",
+		"zone":       "Sandbox-simulator",
+	})
+	if err != nil {
+		return err
+	}
+
+	diff, err := resourceCloudStackTemplate().Diff(
+		s[0], terraform.NewResourceConfig(raw), testAccProvider.Meta())
+	if err != nil {
+		return err
+	}
+
+	// The deprecated is_ready_timeout is not imported, but only changes the
+	// state
+	if diff.RequiresNew() || diff.Attributes["url"] != nil {
+		return fmt.Errorf("Expected no diff of the URL after importing the template, got: %#v", diff.Attributes)
+	}
+
+	return nil
+}
+
 func testAccCheckCloudStackTemplateExists(
 	n string, template *cloudstack.Template) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
@@ -100,7 +182,7 @@ func testAccCheckCloudStackTemplateExists(
 			return fmt.Errorf("No template ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		tmpl, _, err := cs.Template.GetTemplateByID(rs.Primary.ID, "executable")
 
 		if err != nil {
@@ -166,7 +248,7 @@ func testAccCheckCloudStackTemplateUpdatedAttributes(
 }
 
 func testAccCheckCloudStackTemplateDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_template" {
@@ -211,3 +293,15 @@ resource "cloudstack_template" "foo" {
   password_enabled = true
   zone = "Sandbox-simulator"
 }`, cloudStackTemplateURL)
+
+// The simulator doesn't return the URL of a template, like some versions of
+// CloudStack
+const testAccCloudStackTemplate_withoutURL = `
+resource "cloudstack_template" "foo" {
+  name = "terraform-test"
+  format = "QCOW2"
+  hypervisor = "Simulator"
+  os_type = "CentOS 5.6 (64-bit)"
+  url = "http://example.com/terraform-test.qcow2"
+  zone = "Sandbox-simulator"
+}`
@@ -0,0 +1,305 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceCloudStackUser() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceCloudStackUserCreate,
+		Read:   resourceCloudStackUserRead,
+		Update: resourceCloudStackUserUpdate,
+		Delete: resourceCloudStackUserDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStatePassthrough,
+		},
+
+		CustomizeDiff: resourceCloudStackUserCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"account": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"password": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+
+			"email": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"first_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"last_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"timezone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"generate_keys": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"api_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"secret_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceCloudStackUserCreate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	username := d.Get("username").(string)
+
+	// Create a new parameter struct
+	p := cs.User.NewCreateUserParams(
+		d.Get("account").(string),
+		d.Get("email").(string),
+		d.Get("first_name").(string),
+		d.Get("last_name").(string),
+		d.Get("password").(string),
+		username,
+	)
+
+	// If the account is supplied by ID, we retrieve the name and domain of the account
+	if account := d.Get("account").(string); cloudstack.IsID(account) {
+		a, _, err := cs.Account.GetAccountByID(account)
+		if err != nil {
+			return fmt.Errorf("Error retrieving account %s: %s", account, err)
+		}
+		p.SetAccount(a.Name)
+		p.SetDomainid(a.Domainid)
+	}
+
+	// If there is a domain supplied, we retrieve and set the domain id
+	if domain, ok := d.GetOk("domain"); ok {
+		domainid, e := retrieveID(cs, "domain", domain.(string))
+		if e != nil {
+			return e.Error()
+		}
+		p.SetDomainid(domainid)
+	}
+
+	if timezone, ok := d.GetOk("timezone"); ok {
+		p.SetTimezone(timezone.(string))
+	}
+
+	log.Printf("[DEBUG] Creating user %s", username)
+	r, err := cs.User.CreateUser(p)
+	if err != nil {
+		return fmt.Errorf("Error creating user %s: %s", username, err)
+	}
+
+	d.SetId(r.Id)
+
+	// Generate the API keys of the user if requested
+	if d.Get("generate_keys").(bool) {
+		if err := registerUserKeys(cs, d); err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackUserRead(d, meta)
+}
+
+// resourceCloudStackUserCustomizeDiff replaces the user when generate_keys is
+// disabled, as the API has no way to remove the keys of a user and they would
+// otherwise remain valid.
+func resourceCloudStackUserCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && d.HasChange("generate_keys") && !d.Get("generate_keys").(bool) {
+		return d.ForceNew("generate_keys")
+	}
+
+	return nil
+}
+
+func resourceCloudStackUserRead(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	// Get the user details
+	u, count, err := cs.User.GetUserByID(d.Id())
+	if err != nil {
+		if count == 0 || isNotFound(err) {
+			log.Printf("[DEBUG] User %s does no longer exist", d.Get("username").(string))
+			d.SetId("")
+			return nil
+		}
+
+		return err
+	}
+
+	d.Set("username", u.Username)
+	d.Set("email", u.Email)
+	d.Set("first_name", u.Firstname)
+	d.Set("last_name", u.Lastname)
+	d.Set("timezone", u.Timezone)
+	d.Set("state", u.State)
+
+	if !cloudstack.IsID(d.Get("account").(string)) {
+		d.Set("account", u.Account)
+	}
+	setDomain(d, "domain", u.Domain, u.Domainid)
+
+	// Newer versions of CloudStack no longer return the secret key of a user,
+	// so the keys are only updated when both are returned
+	if u.Apikey != "" && u.Secretkey != "" {
+		d.Set("api_key", u.Apikey)
+		d.Set("secret_key", u.Secretkey)
+	}
+
+	return nil
+}
+
+func resourceCloudStackUserUpdate(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	// Check if any of the user details are changed
+	if d.HasChange("username") || d.HasChange("password") || d.HasChange("email") ||
+		d.HasChange("first_name") || d.HasChange("last_name") || d.HasChange("timezone") {
+		if err := updateUser(cs, d, d.Id()); err != nil {
+			return fmt.Errorf("Error updating user %s: %s", d.Get("username").(string), err)
+		}
+	}
+
+	// Check if the API keys should be generated
+	if d.HasChange("generate_keys") && d.Get("generate_keys").(bool) {
+		if err := registerUserKeys(cs, d); err != nil {
+			return err
+		}
+	}
+
+	return resourceCloudStackUserRead(d, meta)
+}
+
+func resourceCloudStackUserDelete(d *schema.ResourceData, meta interface{}) error {
+	cs := meta.(*providerMeta)
+
+	// Create a new parameter struct
+	p := cs.User.NewDeleteUserParams(d.Id())
+
+	// Delete the user
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.User.DeleteUser(p)
+		return err
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("Error deleting user %s: %s", d.Get("username").(string), err)
+	}
+
+	return nil
+}
+
+// updateUser updates the details of the given user that are changed.
+func updateUser(cs *providerMeta, d *schema.ResourceData, userid string) error {
+	// Create a new parameter struct
+	p := cs.User.NewUpdateUserParams(userid)
+
+	if d.HasChange("username") {
+		p.SetUsername(d.Get("username").(string))
+	}
+
+	if d.HasChange("password") {
+		p.SetPassword(d.Get("password").(string))
+	}
+
+	if d.HasChange("email") {
+		p.SetEmail(d.Get("email").(string))
+	}
+
+	if d.HasChange("first_name") {
+		p.SetFirstname(d.Get("first_name").(string))
+	}
+
+	if d.HasChange("last_name") {
+		p.SetLastname(d.Get("last_name").(string))
+	}
+
+	if d.HasChange("timezone") {
+		p.SetTimezone(d.Get("timezone").(string))
+	}
+
+	_, err := cs.User.UpdateUser(p)
+	return err
+}
+
+// registerUserKeys generates a new API key and secret key for the user.
+func registerUserKeys(cs *providerMeta, d *schema.ResourceData) error {
+	r, err := cs.User.RegisterUserKeys(cs.User.NewRegisterUserKeysParams(d.Id()))
+	if err != nil {
+		return fmt.Errorf("Error generating API keys for user %s: %s", d.Get("username").(string), err)
+	}
+
+	d.Set("api_key", r.Apikey)
+	d.Set("secret_key", r.Secretkey)
+
+	return nil
+}
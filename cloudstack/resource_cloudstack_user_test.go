@@ -0,0 +1,239 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccCloudStackUser_basic(t *testing.T) {
+	var user cloudstack.User
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackUser_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackUserExists("cloudstack_user.foo", &user),
+					testAccCheckCloudStackUserAttributes(&user),
+					resource.TestCheckResourceAttr(
+						"cloudstack_user.foo", "account", "tenant"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_user.foo", "domain", "ROOT/tenants"),
+					resource.TestCheckNoResourceAttr(
+						"cloudstack_user.foo", "api_key"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackUser_keys(t *testing.T) {
+	var user cloudstack.User
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackUser_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackUserExists("cloudstack_user.foo", &user),
+					resource.TestCheckNoResourceAttr(
+						"cloudstack_user.foo", "api_key"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackUser_keys,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackUserExists("cloudstack_user.foo", &user),
+					testAccCheckCloudStackUserKeys("cloudstack_user.foo", &user),
+					resource.TestCheckResourceAttr(
+						"cloudstack_user.foo", "email", "updated@example.com"),
+				),
+			},
+
+			{
+				Config: testAccCloudStackUser_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackUserReplaced("cloudstack_user.foo", &user),
+					testAccCheckCloudStackUserExists("cloudstack_user.foo", &user),
+					resource.TestCheckNoResourceAttr(
+						"cloudstack_user.foo", "api_key"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudStackUser_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackUser_keys,
+			},
+
+			{
+				ResourceName:            "cloudstack_user.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"domain", "generate_keys", "password"},
+			},
+		},
+	})
+}
+
+func testAccCheckCloudStackUserExists(
+	n string, user *cloudstack.User) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No user ID is set")
+		}
+
+		cs := testAccProvider.Meta().(*providerMeta)
+		u, _, err := cs.User.GetUserByID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if u.Id != rs.Primary.ID {
+			return fmt.Errorf("User not found")
+		}
+
+		*user = *u
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackUserAttributes(
+	user *cloudstack.User) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if user.Username != "terraform-user" {
+			return fmt.Errorf("Bad username: %s", user.Username)
+		}
+
+		if user.Account != "tenant" {
+			return fmt.Errorf("Bad account: %s", user.Account)
+		}
+
+		if user.Email != "terraform@example.com" {
+			return fmt.Errorf("Bad email: %s", user.Email)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackUserKeys(
+	n string, user *cloudstack.User) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs := s.RootModule().Resources[n]
+
+		if user.Apikey == "" || rs.Primary.Attributes["api_key"] != user.Apikey {
+			return fmt.Errorf("Bad API key: %s", rs.Primary.Attributes["api_key"])
+		}
+
+		if user.Secretkey == "" || rs.Primary.Attributes["secret_key"] != user.Secretkey {
+			return fmt.Errorf("Bad secret key")
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackUserReplaced(
+	n string, user *cloudstack.User) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == user.Id {
+			return fmt.Errorf("Expected user %s to be replaced", user.Id)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckCloudStackUserDestroy(s *terraform.State) error {
+	cs := testAccProvider.Meta().(*providerMeta)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "cloudstack_user" {
+			continue
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No user ID is set")
+		}
+
+		_, _, err := cs.User.GetUserByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("User %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCloudStackUser_basic = `
+resource "cloudstack_user" "foo" {
+  account    = "tenant"
+  domain     = "ROOT/tenants"
+  username   = "terraform-user"
+  password   = "s3cr3t"
+  email      = "terraform@example.com"
+  first_name = "Terraform"
+  last_name  = "User"
+}`
+
+const testAccCloudStackUser_keys = `
+resource "cloudstack_user" "foo" {
+  account       = "tenant"
+  domain        = "ROOT/tenants"
+  username      = "terraform-user"
+  password      = "s3cr3t"
+  email         = "updated@example.com"
+  first_name    = "Terraform"
+  last_name     = "User"
+  generate_keys = true
+}`
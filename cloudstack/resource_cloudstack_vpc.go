@@ -22,7 +22,6 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
@@ -38,6 +37,14 @@ func resourceCloudStackVPC() *schema.Resource {
 			State: importStatePassthrough,
 		},
 
+		CustomizeDiff: validateReferences("vpc_offering", "project", "zone"),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: providerTimeout(),
+			Update: providerTimeout(),
+			Delete: providerTimeout(),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -51,9 +58,10 @@ func resourceCloudStackVPC() *schema.Resource {
 			},
 
 			"cidr": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateCIDR,
 			},
 
 			"vpc_offering": {
@@ -76,6 +84,21 @@ func resourceCloudStackVPC() *schema.Resource {
 				ForceNew: true,
 			},
 
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+
 			"source_nat_ip": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -93,18 +116,23 @@ func resourceCloudStackVPC() *schema.Resource {
 }
 
 func resourceCloudStackVPCCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutCreate))
 
 	name := d.Get("name").(string)
 
-	// Retrieve the vpc_offering ID
-	vpcofferingid, e := retrieveID(cs, "vpc_offering", d.Get("vpc_offering").(string))
+	// Retrieve the zone ID
+	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
 	if e != nil {
 		return e.Error()
 	}
 
-	// Retrieve the zone ID
-	zoneid, e := retrieveID(cs, "zone", d.Get("zone").(string))
+	scope, err := lookupScope(cs, d, zoneid)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve the vpc_offering ID
+	vpcofferingid, e := retrieveScopedID(cs, "vpc_offering", d.Get("vpc_offering").(string), scope)
 	if e != nil {
 		return e.Error()
 	}
@@ -135,6 +163,11 @@ func resourceCloudStackVPCCreate(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// Create the new VPC
 	r, err := cs.VPC.CreateVPC(p)
 	if err != nil {
@@ -153,15 +186,16 @@ func resourceCloudStackVPCCreate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceCloudStackVPCRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the VPC details
 	v, count, err := cs.VPC.GetVPCByID(
 		d.Id(),
 		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
 	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf(
 				"[DEBUG] VPC %s does no longer exist", d.Get("name").(string))
 			d.SetId("")
@@ -190,6 +224,7 @@ func resourceCloudStackVPCRead(d *schema.ResourceData, meta interface{}) error {
 
 	setValueOrID(d, "vpc_offering", o.Name, v.Vpcofferingid)
 	setValueOrID(d, "project", v.Project, v.Projectid)
+	setAccountAndDomain(d, v.Account, v.Domain, v.Domainid)
 	setValueOrID(d, "zone", v.Zonename, v.Zoneid)
 
 	// Create a new parameter struct
@@ -202,6 +237,11 @@ func resourceCloudStackVPCRead(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// Get the source NAT IP assigned to the VPC
 	l, err := cs.Address.ListPublicIpAddresses(p)
 	if err != nil {
@@ -216,7 +256,7 @@ func resourceCloudStackVPCRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceCloudStackVPCUpdate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutUpdate))
 
 	name := d.Get("name").(string)
 
@@ -271,18 +311,18 @@ func resourceCloudStackVPCUpdate(d *schema.ResourceData, meta interface{}) error
 }
 
 func resourceCloudStackVPCDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutDelete))
 
 	// Create a new parameter struct
 	p := cs.VPC.NewDeleteVPCParams(d.Id())
 
 	// Delete the VPC
-	_, err := cs.VPC.DeleteVPC(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.VPC.DeleteVPC(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
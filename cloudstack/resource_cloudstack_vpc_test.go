@@ -51,6 +51,26 @@ func TestAccCloudStackVPC_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackVPC_timeouts(t *testing.T) {
+	var vpc cloudstack.VPC
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVPCDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVPC_timeouts,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVPCExists(
+						"cloudstack_vpc.foo", &vpc),
+					testAccCheckCloudStackVPCAttributes(&vpc),
+				),
+			},
+		},
+	})
+}
+
 func TestAccCloudStackVPC_import(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -70,6 +90,46 @@ func TestAccCloudStackVPC_import(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackVPC_account(t *testing.T) {
+	var vpc cloudstack.VPC
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVPCDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVPC_account,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCloudStackVPCExists(
+						"cloudstack_vpc.foo", &vpc),
+					testAccCheckCloudStackVPCAccount(&vpc, "tenant", "tenants"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vpc.foo", "account", "tenant"),
+					resource.TestCheckResourceAttr(
+						"cloudstack_vpc.foo", "domain", "ROOT/tenants"),
+				),
+			},
+
+			{
+				ResourceName:      "cloudstack_vpc.foo",
+				ImportState:       true,
+				ImportStateIdFunc: testAccCloudStackVPCAccountImportStateID,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCloudStackVPCAccountImportStateID(s *terraform.State) (string, error) {
+	rs, ok := s.RootModule().Resources["cloudstack_vpc.foo"]
+	if !ok {
+		return "", fmt.Errorf("Not found: cloudstack_vpc.foo")
+	}
+
+	return fmt.Sprintf("ROOT/tenants/tenant/%s", rs.Primary.ID), nil
+}
+
 func testAccCheckCloudStackVPCExists(
 	n string, vpc *cloudstack.VPC) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
@@ -82,7 +142,7 @@ func testAccCheckCloudStackVPCExists(
 			return fmt.Errorf("No VPC ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		v, _, err := cs.VPC.GetVPCByID(rs.Primary.ID)
 
 		if err != nil {
@@ -123,8 +183,24 @@ func testAccCheckCloudStackVPCAttributes(
 	}
 }
 
+func testAccCheckCloudStackVPCAccount(
+	vpc *cloudstack.VPC, account string, domain string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if vpc.Account != account {
+			return fmt.Errorf("Bad account: %s", vpc.Account)
+		}
+
+		if vpc.Domain != domain {
+			return fmt.Errorf("Bad domain: %s", vpc.Domain)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckCloudStackVPCDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_vpc" {
@@ -156,3 +232,28 @@ resource "cloudstack_vpc" "foo" {
     terraform-tag = "true"
   }
 }`
+
+const testAccCloudStackVPC_timeouts = `
+resource "cloudstack_vpc" "foo" {
+  name = "terraform-vpc"
+  display_text = "terraform-vpc-text"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  network_domain = "terraform-domain"
+  zone = "Sandbox-simulator"
+
+  timeouts {
+    create = "5m"
+    delete = "2m"
+  }
+}`
+
+const testAccCloudStackVPC_account = `
+resource "cloudstack_vpc" "foo" {
+  name = "terraform-vpc"
+  cidr = "10.0.0.0/8"
+  vpc_offering = "Default VPC offering"
+  zone = "Sandbox-simulator"
+  account = "tenant"
+  domain = "ROOT/tenants"
+}`
@@ -22,10 +22,8 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 func resourceCloudStackVPNConnection() *schema.Resource {
@@ -33,6 +31,14 @@ func resourceCloudStackVPNConnection() *schema.Resource {
 		Create: resourceCloudStackVPNConnectionCreate,
 		Read:   resourceCloudStackVPNConnectionRead,
 		Delete: resourceCloudStackVPNConnectionDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: providerTimeout(),
+			Delete: providerTimeout(),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"customer_gateway_id": {
@@ -51,7 +57,7 @@ func resourceCloudStackVPNConnection() *schema.Resource {
 }
 
 func resourceCloudStackVPNConnectionCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutCreate))
 
 	// Create a new parameter struct
 	p := cs.VPN.NewCreateVpnConnectionParams(
@@ -71,12 +77,12 @@ func resourceCloudStackVPNConnectionCreate(d *schema.ResourceData, meta interfac
 }
 
 func resourceCloudStackVPNConnectionRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the VPN Connection details
 	v, count, err := cs.VPN.GetVpnConnectionByID(d.Id())
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf("[DEBUG] VPN Connection does no longer exist")
 			d.SetId("")
 			return nil
@@ -92,18 +98,18 @@ func resourceCloudStackVPNConnectionRead(d *schema.ResourceData, meta interface{
 }
 
 func resourceCloudStackVPNConnectionDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutDelete))
 
 	// Create a new parameter struct
 	p := cs.VPN.NewDeleteVpnConnectionParams(d.Id())
 
 	// Delete the VPN Connection
-	_, err := cs.VPN.DeleteVpnConnection(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.VPN.DeleteVpnConnection(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -49,6 +49,25 @@ func TestAccCloudStackVPNConnection_basic(t *testing.T) {
 	})
 }
 
+func TestAccCloudStackVPNConnection_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckCloudStackVPNConnectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCloudStackVPNConnection_basic,
+			},
+
+			{
+				ResourceName:      "cloudstack_vpn_connection.foo-bar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccCheckCloudStackVPNConnectionExists(
 	n string, vpnConnection *cloudstack.VpnConnection) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
@@ -61,7 +80,7 @@ func testAccCheckCloudStackVPNConnectionExists(
 			return fmt.Errorf("No VPN Connection ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		v, _, err := cs.VPN.GetVpnConnectionByID(rs.Primary.ID)
 
 		if err != nil {
@@ -79,7 +98,7 @@ func testAccCheckCloudStackVPNConnectionExists(
 }
 
 func testAccCheckCloudStackVPNConnectionDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_vpn_connection" {
@@ -22,9 +22,9 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
@@ -38,6 +38,12 @@ func resourceCloudStackVPNCustomerGateway() *schema.Resource {
 			State: importStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: providerTimeout(),
+			Update: providerTimeout(),
+			Delete: providerTimeout(),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -45,8 +51,9 @@ func resourceCloudStackVPNCustomerGateway() *schema.Resource {
 			},
 
 			"cidr": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateCIDRList,
 			},
 
 			"esp_policy": {
@@ -55,8 +62,9 @@ func resourceCloudStackVPNCustomerGateway() *schema.Resource {
 			},
 
 			"gateway": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIPAddress,
 			},
 
 			"ike_policy": {
@@ -76,18 +84,35 @@ func resourceCloudStackVPNCustomerGateway() *schema.Resource {
 			},
 
 			"esp_lifetime": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Computed: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(0, 86400),
 			},
 
 			"ike_lifetime": {
-				Type:     schema.TypeInt,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(0, 86400),
+			},
+
+			"project": {
+				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
+				ForceNew: true,
 			},
 
-			"project": {
+			"account": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"project"},
+			},
+
+			"domain": {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
@@ -98,7 +123,7 @@ func resourceCloudStackVPNCustomerGateway() *schema.Resource {
 }
 
 func resourceCloudStackVPNCustomerGatewayCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutCreate))
 
 	// Create a new parameter struct
 	p := cs.VPN.NewCreateVpnCustomerGatewayParams(
@@ -128,6 +153,11 @@ func resourceCloudStackVPNCustomerGatewayCreate(d *schema.ResourceData, meta int
 		return err
 	}
 
+	// If there is an account supplied, we retrieve and set the account and domain id
+	if err := setAccountid(p, cs, d); err != nil {
+		return err
+	}
+
 	// Create the new VPN Customer Gateway
 	v, err := cs.VPN.CreateVpnCustomerGateway(p)
 	if err != nil {
@@ -140,12 +170,16 @@ func resourceCloudStackVPNCustomerGatewayCreate(d *schema.ResourceData, meta int
 }
 
 func resourceCloudStackVPNCustomerGatewayRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the VPN Customer Gateway details
-	v, count, err := cs.VPN.GetVpnCustomerGatewayByID(d.Id())
+	v, count, err := cs.VPN.GetVpnCustomerGatewayByID(
+		d.Id(),
+		cloudstack.WithProject(d.Get("project").(string)),
+		withAccount(cs, d),
+	)
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf(
 				"[DEBUG] VPN Customer Gateway %s does no longer exist", d.Get("name").(string))
 			d.SetId("")
@@ -166,12 +200,13 @@ func resourceCloudStackVPNCustomerGatewayRead(d *schema.ResourceData, meta inter
 	d.Set("ike_lifetime", int(v.Ikelifetime))
 
 	setValueOrID(d, "project", v.Project, v.Projectid)
+	setAccountAndDomain(d, v.Account, v.Domain, v.Domainid)
 
 	return nil
 }
 
 func resourceCloudStackVPNCustomerGatewayUpdate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutUpdate))
 
 	// Create a new parameter struct
 	p := cs.VPN.NewUpdateVpnCustomerGatewayParams(
@@ -207,18 +242,18 @@ func resourceCloudStackVPNCustomerGatewayUpdate(d *schema.ResourceData, meta int
 }
 
 func resourceCloudStackVPNCustomerGatewayDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutDelete))
 
 	// Create a new parameter struct
 	p := cs.VPN.NewDeleteVpnCustomerGatewayParams(d.Id())
 
 	// Delete the VPN Customer Gateway
-	_, err := cs.VPN.DeleteVpnCustomerGateway(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.VPN.DeleteVpnCustomerGateway(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -132,7 +132,7 @@ func testAccCheckCloudStackVPNCustomerGatewayExists(
 			return fmt.Errorf("No VPN CustomerGateway ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		v, _, err := cs.VPN.GetVpnCustomerGatewayByID(rs.Primary.ID)
 
 		if err != nil {
@@ -190,7 +190,7 @@ func testAccCheckCloudStackVPNCustomerGatewayUpdatedAttributes(
 }
 
 func testAccCheckCloudStackVPNCustomerGatewayDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_vpn_customer_gateway" {
@@ -22,10 +22,8 @@ package cloudstack
 import (
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 func resourceCloudStackVPNGateway() *schema.Resource {
@@ -37,6 +35,11 @@ func resourceCloudStackVPNGateway() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: providerTimeout(),
+			Delete: providerTimeout(),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"vpc_id": {
 				Type:     schema.TypeString,
@@ -53,7 +56,7 @@ func resourceCloudStackVPNGateway() *schema.Resource {
 }
 
 func resourceCloudStackVPNGatewayCreate(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutCreate))
 
 	vpcid := d.Get("vpc_id").(string)
 	p := cs.VPN.NewCreateVpnGatewayParams(vpcid)
@@ -70,12 +73,12 @@ func resourceCloudStackVPNGatewayCreate(d *schema.ResourceData, meta interface{}
 }
 
 func resourceCloudStackVPNGatewayRead(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := meta.(*providerMeta)
 
 	// Get the VPN Gateway details
 	v, count, err := cs.VPN.GetVpnGatewayByID(d.Id())
 	if err != nil {
-		if count == 0 {
+		if count == 0 || isNotFound(err) {
 			log.Printf(
 				"[DEBUG] VPN Gateway for VPC ID %s does no longer exist", d.Get("vpc_id").(string))
 			d.SetId("")
@@ -92,18 +95,18 @@ func resourceCloudStackVPNGatewayRead(d *schema.ResourceData, meta interface{})
 }
 
 func resourceCloudStackVPNGatewayDelete(d *schema.ResourceData, meta interface{}) error {
-	cs := meta.(*cloudstack.CloudStackClient)
+	cs := withTimeout(meta.(*providerMeta), d.Timeout(schema.TimeoutDelete))
 
 	// Create a new parameter struct
 	p := cs.VPN.NewDeleteVpnGatewayParams(d.Id())
 
 	// Delete the VPN Gateway
-	_, err := cs.VPN.DeleteVpnGateway(p)
+	err := retryTransient(d.Timeout(schema.TimeoutDelete), func() error {
+		_, err := cs.VPN.DeleteVpnGateway(p)
+		return err
+	})
 	if err != nil {
-		// This is a very poor way to be told the ID does no longer exist :(
-		if strings.Contains(err.Error(), fmt.Sprintf(
-			"Invalid parameter id value=%s due to incorrect long value format, "+
-				"or entity does not exist", d.Id())) {
+		if isNotFound(err) {
 			return nil
 		}
 
@@ -78,7 +78,7 @@ func testAccCheckCloudStackVPNGatewayExists(
 			return fmt.Errorf("No VPN Gateway ID is set")
 		}
 
-		cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+		cs := testAccProvider.Meta().(*providerMeta)
 		v, _, err := cs.VPN.GetVpnGatewayByID(rs.Primary.ID)
 
 		if err != nil {
@@ -96,7 +96,7 @@ func testAccCheckCloudStackVPNGatewayExists(
 }
 
 func testAccCheckCloudStackVPNGatewayDestroy(s *terraform.State) error {
-	cs := testAccProvider.Meta().(*cloudstack.CloudStackClient)
+	cs := testAccProvider.Meta().(*providerMeta)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "cloudstack_vpn_gateway" {
@@ -21,7 +21,6 @@ package cloudstack
 
 import (
 	"fmt"
-	"log"
 	"regexp"
 	"strings"
 	"time"
@@ -57,82 +56,57 @@ func setValueOrID(d *schema.ResourceData, key string, value string, id string) {
 	}
 }
 
-func retrieveID(cs *cloudstack.CloudStackClient, name string, value string, opts ...cloudstack.OptionFunc) (id string, e *retrieveError) {
+func retrieveID(cs *providerMeta, name string, value string) (id string, e *retrieveError) {
+	return retrieveScopedID(cs, name, value, idScope{})
+}
+
+// retrieveScopedID retrieves the ID of a named object like retrieveID, but
+// uses the scope to select the object when the name isn't unique.
+func retrieveScopedID(cs *providerMeta, name string, value string, scope idScope) (id string, e *retrieveError) {
 	// If the supplied value isn't a ID, try to retrieve the ID ourselves
 	if cloudstack.IsID(value) {
 		return value, nil
 	}
 
-	log.Printf("[DEBUG] Retrieving ID of %s: %s", name, value)
-
-	// Ignore counts, since an error is returned if there is no exact match
-	var err error
-	switch name {
-	case "disk_offering":
-		id, _, err = cs.DiskOffering.GetDiskOfferingID(value)
-	case "service_offering":
-		id, _, err = cs.ServiceOffering.GetServiceOfferingID(value)
-	case "network_offering":
-		id, _, err = cs.NetworkOffering.GetNetworkOfferingID(value)
-	case "project":
-		id, _, err = cs.Project.GetProjectID(value)
-	case "vpc_offering":
-		id, _, err = cs.VPC.GetVPCOfferingID(value)
-	case "zone":
-		id, _, err = cs.Zone.GetZoneID(value)
-	case "os_type":
-		p := cs.GuestOS.NewListOsTypesParams()
-		p.SetDescription(value)
-		l, e := cs.GuestOS.ListOsTypes(p)
-		if e != nil {
-			err = e
-			break
-		}
-		if l.Count == 1 {
-			id = l.OsTypes[0].Id
-			break
-		}
-		err = fmt.Errorf("Could not find ID of OS Type: %s", value)
-	default:
+	if _, ok := idCacheTTL[name]; !ok {
 		return id, &retrieveError{name: name, value: value,
 			err: fmt.Errorf("Unknown request: %s", name)}
 	}
 
-	if err != nil {
-		return id, &retrieveError{name: name, value: value, err: err}
+	lookup := func() (string, error) {
+		return lookupID(cs, name, value, scope)
 	}
 
-	return id, nil
-}
-
-func retrieveTemplateID(cs *cloudstack.CloudStackClient, zoneid, value string) (id string, e *retrieveError) {
-	// If the supplied value isn't a ID, try to retrieve the ID ourselves
-	if cloudstack.IsID(value) {
-		return value, nil
+	var err error
+	if cs.ids != nil {
+		id, err = cs.ids.resolve(name, scope.key(), value, lookup)
+	} else {
+		id, err = lookup()
 	}
 
-	log.Printf("[DEBUG] Retrieving ID of template: %s", value)
-
-	// Ignore count, since an error is returned if there is no exact match
-	id, _, err := cs.Template.GetTemplateID(value, "executable", zoneid)
 	if err != nil {
-		return id, &retrieveError{name: "template", value: value, err: err}
+		return id, &retrieveError{name: name, value: value, err: err}
 	}
 
 	return id, nil
 }
 
+func retrieveTemplateID(cs *providerMeta, zoneid, value string) (id string, e *retrieveError) {
+	return retrieveScopedID(cs, "template", value, idScope{zoneid: zoneid})
+}
+
 // RetryFunc is the function retried n times
 type RetryFunc func() (interface{}, error)
 
 // Retry is a wrapper around a RetryFunc that will retry a function
-// n times or until it succeeds.
+// n times or until it succeeds. Errors that cannot be resolved by
+// retrying, like a missing entity or permission, are returned directly.
 func Retry(n int, f RetryFunc) (interface{}, error) {
 	var lastErr error
 
 	for i := 0; i < n; i++ {
 		r, err := f()
-		if err == nil || err == cloudstack.AsyncTimeoutErr {
+		if err == nil || err == cloudstack.AsyncTimeoutErr || isNotFound(err) || isUnauthorized(err) {
 			return r, err
 		}
 
@@ -144,27 +118,150 @@ func Retry(n int, f RetryFunc) (interface{}, error) {
 }
 
 // If there is a project supplied, we retrieve and set the project id
-func setProjectid(p cloudstack.ProjectIDSetter, cs *cloudstack.CloudStackClient, d *schema.ResourceData) error {
-	if project, ok := d.GetOk("project"); ok {
-		projectid, e := retrieveID(cs, "project", project.(string))
+func setProjectid(p cloudstack.ProjectIDSetter, cs *providerMeta, d *schema.ResourceData) error {
+	if _, ok := d.GetOk("project"); ok {
+		// Project names are only unique within a domain, so the
+		// project is looked up within the domain when one is set
+		scope, err := lookupScope(cs, d, "")
+		if err != nil {
+			return err
+		}
+		p.SetProjectid(scope.projectid)
+	}
+
+	return nil
+}
+
+// lookupScope returns the scope used to look up the names referenced by a
+// resource, based on the given zone ID and the domain and project of the
+// resource, if it has those.
+func lookupScope(cs *providerMeta, d *schema.ResourceData, zoneid string) (idScope, error) {
+	scope := idScope{zoneid: zoneid}
+
+	if domain, ok := d.Get("domain").(string); ok && domain != "" {
+		domainid, e := retrieveID(cs, "domain", domain)
+		if e != nil {
+			return scope, e.Error()
+		}
+		scope.domainid = domainid
+	}
+
+	if project, ok := d.Get("project").(string); ok && project != "" {
+		projectid, e := retrieveScopedID(cs, "project", project, idScope{domainid: scope.domainid})
+		if e != nil {
+			return scope, e.Error()
+		}
+		scope.projectid = projectid
+	}
+
+	return scope, nil
+}
+
+// accountSetter is an interface that every type that can set an account must implement
+type accountSetter interface {
+	SetAccount(string)
+	SetDomainid(string)
+}
+
+// If there is an account or domain supplied, we retrieve and set the account
+// name and domain id
+func setAccountid(p accountSetter, cs *providerMeta, d *schema.ResourceData) error {
+	_, domainOK := d.GetOk("domain")
+	if _, ok := d.GetOk("account"); ok && !domainOK {
+		return fmt.Errorf("A 'domain' is required when an 'account' is supplied")
+	}
+
+	return withAccount(cs, d)(cs.CloudStackClient, p)
+}
+
+// withAccount takes the account and domain (either a name or ID) of a resource
+// and sets the `account` and `domainid` parameters. Resources that belong to a
+// project are scoped by their project instead.
+func withAccount(cs *providerMeta, d *schema.ResourceData) cloudstack.OptionFunc {
+	return func(_ *cloudstack.CloudStackClient, p interface{}) error {
+		domain, ok := d.Get("domain").(string)
+		if !ok || domain == "" {
+			return nil
+		}
+
+		if project, ok := d.Get("project").(string); ok && project != "" {
+			return nil
+		}
+
+		domainid, e := retrieveID(cs, "domain", domain)
 		if e != nil {
 			return e.Error()
 		}
-		p.SetProjectid(projectid)
+
+		account, _ := d.Get("account").(string)
+		if account != "" && cloudstack.IsID(account) {
+			a, _, err := cs.Account.GetAccountByID(account)
+			if err != nil {
+				return fmt.Errorf("Error retrieving name of account %s: %s", account, err)
+			}
+			account = a.Name
+		}
+
+		switch ps := p.(type) {
+		case accountSetter:
+			if account != "" {
+				ps.SetAccount(account)
+			}
+			ps.SetDomainid(domainid)
+		case cloudstack.DomainIDSetter:
+			ps.SetDomainid(domainid)
+		}
+
+		return nil
 	}
+}
 
-	return nil
+// setAccountAndDomain sets the account and domain of a resource, keeping the
+// configured account if it is an ID.
+func setAccountAndDomain(d *schema.ResourceData, account string, domain string, domainid string) {
+	if !cloudstack.IsID(d.Get("account").(string)) {
+		d.Set("account", account)
+	}
+	setDomain(d, "domain", domain, domainid)
 }
 
-// importStatePassthrough is a generic importer with project support.
+// setDomain sets the domain of a resource, keeping the configured value if it
+// is an ID or a domain path ending in the domain name.
+func setDomain(d *schema.ResourceData, key string, domain string, domainid string) {
+	if path := d.Get(key).(string); strings.HasSuffix(path, "/"+domain) {
+		return
+	}
+	setValueOrID(d, key, domain, domainid)
+}
+
+// importStatePassthrough is a generic importer with project and account
+// support. The ID can be prefixed with either a project (project/id) or
+// a domain and account (domain/account/id).
 func importStatePassthrough(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	// Try to split the ID to extract the optional project name.
-	s := strings.SplitN(d.Id(), "/", 2)
-	if len(s) == 2 {
+	// Try to split the ID to extract the optional project, or domain and
+	// account name. A domain path can contain slashes itself.
+	s := strings.Split(d.Id(), "/")
+	switch {
+	case len(s) == 2:
 		d.Set("project", s[0])
+	case len(s) > 2:
+		d.Set("domain", strings.Join(s[:len(s)-2], "/"))
+		d.Set("account", s[len(s)-2])
 	}
 
 	d.SetId(s[len(s)-1])
 
 	return []*schema.ResourceData{d}, nil
 }
+
+// splitImportID splits the import ID of a resource that can only be found
+// through its parent, like the NIC of a virtual machine, into the ID of the
+// parent and the ID of the resource itself.
+func splitImportID(id string, format string) (string, string, error) {
+	s := strings.Split(id, "/")
+	if len(s) != 2 || s[0] == "" || s[1] == "" {
+		return "", "", fmt.Errorf("Invalid import ID %q, expected %s", id, format)
+	}
+
+	return s[0], s[1], nil
+}
@@ -0,0 +1,135 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestImportStatePassthrough(t *testing.T) {
+	cases := []struct {
+		ID      string
+		Project string
+		Domain  string
+		Account string
+		Result  string
+	}{
+		{ID: "id", Result: "id"},
+		{ID: "terraform/id", Project: "terraform", Result: "id"},
+		{ID: "ROOT/admin/id", Domain: "ROOT", Account: "admin", Result: "id"},
+		{ID: "ROOT/tenants/tenant/id", Domain: "ROOT/tenants", Account: "tenant", Result: "id"},
+	}
+
+	for i, tc := range cases {
+		d := resourceCloudStackVPC().TestResourceData()
+		d.SetId(tc.ID)
+
+		r, err := importStatePassthrough(d, nil)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+
+		d = r[0]
+		if d.Id() != tc.Result {
+			t.Fatalf("%d: bad ID: %s", i, d.Id())
+		}
+		if d.Get("project").(string) != tc.Project {
+			t.Fatalf("%d: bad project: %s", i, d.Get("project"))
+		}
+		if d.Get("domain").(string) != tc.Domain {
+			t.Fatalf("%d: bad domain: %s", i, d.Get("domain"))
+		}
+		if d.Get("account").(string) != tc.Account {
+			t.Fatalf("%d: bad account: %s", i, d.Get("account"))
+		}
+	}
+}
+
+func TestSplitImportID(t *testing.T) {
+	cases := []struct {
+		ID     string
+		Parent string
+		Result string
+		Err    bool
+	}{
+		{ID: "vm/id", Parent: "vm", Result: "id"},
+		{ID: "id", Err: true},
+		{ID: "vm/", Err: true},
+		{ID: "/id", Err: true},
+		{ID: "project/vm/id", Err: true},
+	}
+
+	for i, tc := range cases {
+		parent, id, err := splitImportID(tc.ID, "<PARENT ID>/<ID>")
+		if tc.Err {
+			if err == nil {
+				t.Fatalf("%d: expected an error for %q", i, tc.ID)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+		if parent != tc.Parent || id != tc.Result {
+			t.Fatalf("%d: bad IDs: %s, %s", i, parent, id)
+		}
+	}
+}
+
+type testAccountParams struct {
+	account  string
+	domainid string
+}
+
+func (p *testAccountParams) SetAccount(v string)  { p.account = v }
+func (p *testAccountParams) SetDomainid(v string) { p.domainid = v }
+
+func TestSetAccountid(t *testing.T) {
+	s, cs := newSimulatorClient(t)
+	defer s.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceCloudStackVPC().Schema, map[string]interface{}{
+		"account": "tenant",
+	})
+
+	p := &testAccountParams{}
+	if err := setAccountid(p, cs, d); err == nil {
+		t.Fatal("Expected an error for an account without a domain")
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceCloudStackVPC().Schema, map[string]interface{}{
+		"account": s.ID("account", "tenant"),
+		"domain":  "tenants",
+	})
+
+	if err := setAccountid(p, cs, d); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// The account ID is resolved to its name, the domain name to its ID
+	if p.account != "tenant" {
+		t.Fatalf("Bad account: %s", p.account)
+	}
+	if p.domainid != s.ID("domain", "tenants") {
+		t.Fatalf("Bad domain ID: %s", p.domainid)
+	}
+}
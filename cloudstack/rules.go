@@ -0,0 +1,144 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// ruleSetRule is a single CloudStack rule, like a firewall or network ACL
+// rule, that is grouped with similar rules into a rule of a rule set when a
+// rule set resource is imported.
+type ruleSetRule struct {
+	id        string
+	protocol  string
+	cidrs     []string
+	startPort int
+	endPort   int
+	icmpType  int
+	icmpCode  int
+
+	// attrs are the other attributes of the rule, like the action of a
+	// network ACL rule. Rules are only grouped if their attributes match.
+	attrs map[string]interface{}
+}
+
+// groupRules groups single CloudStack rules into the rules of a rule set, the
+// reverse of creating the rules of a rule set. TCP and UDP rules with the same
+// CIDR list and attributes are grouped into a single rule with multiple ports,
+// while ICMP rules and rules for all protocols each make up a rule of their own.
+func groupRules(rs []ruleSetRule) []map[string]interface{} {
+	var rules []map[string]interface{}
+	grouped := make(map[string]map[string]interface{})
+
+	for _, r := range rs {
+		cidrs := &schema.Set{F: schema.HashString}
+		for _, cidr := range r.cidrs {
+			cidrs.Add(cidr)
+		}
+
+		if r.protocol == "tcp" || r.protocol == "udp" {
+			key := ruleGroupKey(r)
+			port := portRange(r.startPort, r.endPort)
+
+			if rule, ok := grouped[key]; ok {
+				rule["ports"].(*schema.Set).Add(port)
+				rule["uuids"].(map[string]interface{})[port] = r.id
+				continue
+			}
+
+			ports := &schema.Set{F: schema.HashString}
+			ports.Add(port)
+
+			rule := newGroupedRule(r, cidrs, ports, map[string]interface{}{port: r.id})
+			grouped[key] = rule
+			rules = append(rules, rule)
+			continue
+		}
+
+		// The UUID of other rules is stored by protocol
+		rule := newGroupedRule(r, cidrs, &schema.Set{F: schema.HashString}, map[string]interface{}{r.protocol: r.id})
+		if r.protocol == "icmp" {
+			rule["icmp_type"] = r.icmpType
+			rule["icmp_code"] = r.icmpCode
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+func newGroupedRule(r ruleSetRule, cidrs, ports *schema.Set, uuids map[string]interface{}) map[string]interface{} {
+	rule := map[string]interface{}{
+		"cidr_list": cidrs,
+		"protocol":  r.protocol,
+		"icmp_type": 0,
+		"icmp_code": 0,
+		"ports":     ports,
+		"uuids":     uuids,
+	}
+	for k, v := range r.attrs {
+		rule[k] = v
+	}
+	return rule
+}
+
+// ruleGroupKey returns the key of the group of TCP and UDP rules a rule
+// belongs to.
+func ruleGroupKey(r ruleSetRule) string {
+	cidrs := append([]string(nil), r.cidrs...)
+	sort.Strings(cidrs)
+
+	attrs := make([]string, 0, len(r.attrs))
+	for k, v := range r.attrs {
+		attrs = append(attrs, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(attrs)
+
+	return r.protocol + "|" + strings.Join(cidrs, ",") + "|" + strings.Join(attrs, ",")
+}
+
+// portRange formats a port range the way the ports of a rule are configured.
+// A rule without a port range applies to all ports.
+func portRange(start, end int) string {
+	switch {
+	case start == 0 && end == 0:
+		return "1-65535"
+	case end == 0 || start == end:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d-%d", start, end)
+	}
+}
+
+// splitList splits a comma separated list, like the CIDR list of a rule.
+func splitList(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
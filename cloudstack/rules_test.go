@@ -0,0 +1,117 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestPortRange(t *testing.T) {
+	cases := []struct {
+		Start, End int
+		Expected   string
+	}{
+		{0, 0, "1-65535"},
+		{80, 0, "80"},
+		{80, 80, "80"},
+		{1000, 2000, "1000-2000"},
+	}
+
+	for _, tc := range cases {
+		if got := portRange(tc.Start, tc.End); got != tc.Expected {
+			t.Errorf("portRange(%d, %d): expected %q, got %q", tc.Start, tc.End, tc.Expected, got)
+		}
+	}
+}
+
+func TestGroupRules(t *testing.T) {
+	rules := groupRules([]ruleSetRule{
+		{id: "1", protocol: "tcp", cidrs: []string{"10.0.0.0/24", "10.0.1.0/24"}, startPort: 80, endPort: 80},
+		{id: "2", protocol: "tcp", cidrs: []string{"10.0.1.0/24", "10.0.0.0/24"}, startPort: 1000, endPort: 2000},
+		{id: "3", protocol: "tcp", cidrs: []string{"10.0.0.0/24"}, startPort: 443, endPort: 443},
+		{id: "4", protocol: "udp", cidrs: []string{"10.0.0.0/24"}, startPort: 53, endPort: 53},
+		{id: "5", protocol: "tcp", cidrs: []string{"10.0.0.0/24"}, startPort: 22, endPort: 22,
+			attrs: map[string]interface{}{"action": "deny"}},
+		{id: "6", protocol: "icmp", cidrs: []string{"0.0.0.0/0"}, icmpType: 8, icmpCode: 0},
+		{id: "7", protocol: "all", cidrs: []string{"0.0.0.0/0"}},
+	})
+
+	expected := []struct {
+		CIDRs    []string
+		Protocol string
+		Ports    []string
+		UUIDs    map[string]interface{}
+		IcmpType int
+		Action   interface{}
+	}{
+		{[]string{"10.0.0.0/24", "10.0.1.0/24"}, "tcp", []string{"1000-2000", "80"},
+			map[string]interface{}{"80": "1", "1000-2000": "2"}, 0, nil},
+		{[]string{"10.0.0.0/24"}, "tcp", []string{"443"},
+			map[string]interface{}{"443": "3"}, 0, nil},
+		{[]string{"10.0.0.0/24"}, "udp", []string{"53"},
+			map[string]interface{}{"53": "4"}, 0, nil},
+		{[]string{"10.0.0.0/24"}, "tcp", []string{"22"},
+			map[string]interface{}{"22": "5"}, 0, "deny"},
+		{[]string{"0.0.0.0/0"}, "icmp", nil,
+			map[string]interface{}{"icmp": "6"}, 8, nil},
+		{[]string{"0.0.0.0/0"}, "all", nil,
+			map[string]interface{}{"all": "7"}, 0, nil},
+	}
+
+	if len(rules) != len(expected) {
+		t.Fatalf("Expected %d rules, got %d: %v", len(expected), len(rules), rules)
+	}
+
+	for i, e := range expected {
+		rule := rules[i]
+
+		if cidrs := sortedStrings(rule["cidr_list"].(*schema.Set)); !reflect.DeepEqual(cidrs, e.CIDRs) {
+			t.Errorf("Rule %d: expected CIDR list %v, got %v", i, e.CIDRs, cidrs)
+		}
+		if rule["protocol"] != e.Protocol {
+			t.Errorf("Rule %d: expected protocol %s, got %v", i, e.Protocol, rule["protocol"])
+		}
+		if ports := sortedStrings(rule["ports"].(*schema.Set)); !reflect.DeepEqual(ports, e.Ports) {
+			t.Errorf("Rule %d: expected ports %v, got %v", i, e.Ports, ports)
+		}
+		if !reflect.DeepEqual(rule["uuids"], e.UUIDs) {
+			t.Errorf("Rule %d: expected UUIDs %v, got %v", i, e.UUIDs, rule["uuids"])
+		}
+		if rule["icmp_type"] != e.IcmpType {
+			t.Errorf("Rule %d: expected ICMP type %d, got %v", i, e.IcmpType, rule["icmp_type"])
+		}
+		if rule["action"] != e.Action {
+			t.Errorf("Rule %d: expected action %v, got %v", i, e.Action, rule["action"])
+		}
+	}
+}
+
+func sortedStrings(s *schema.Set) []string {
+	var l []string
+	for _, v := range s.List() {
+		l = append(l, v.(string))
+	}
+	sort.Strings(l)
+	return l
+}
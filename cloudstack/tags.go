@@ -23,7 +23,6 @@ import (
 	"log"
 
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/apache/cloudstack-go/v2/cloudstack"
 )
 
 // tagsSchema returns the schema to use for tags
@@ -37,7 +36,7 @@ func tagsSchema() *schema.Schema {
 
 // setTags is a helper to set the tags for a resource. It expects the
 // tags field to be named "tags"
-func setTags(cs *cloudstack.CloudStackClient, d *schema.ResourceData, resourcetype string) error {
+func setTags(cs *providerMeta, d *schema.ResourceData, resourcetype string) error {
 	if tags, ok := d.GetOk("tags"); ok {
 		p := cs.Resourcetags.NewCreateTagsParams(
 			[]string{d.Id()},
@@ -54,7 +53,7 @@ func setTags(cs *cloudstack.CloudStackClient, d *schema.ResourceData, resourcety
 
 // updateTags is a helper to update only when tags field change tags
 // field to be named "tags"
-func updateTags(cs *cloudstack.CloudStackClient, d *schema.ResourceData, resourcetype string) error {
+func updateTags(cs *providerMeta, d *schema.ResourceData, resourcetype string) error {
 	oraw, nraw := d.GetChange("tags")
 	o := oraw.(map[string]interface{})
 	n := nraw.(map[string]interface{})
@@ -0,0 +1,49 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// providerTimeout returns the default of the timeouts of resources that wait
+// for long running async jobs. A zero timeout means the provider wide
+// timeout is used.
+func providerTimeout() *time.Duration {
+	return schema.DefaultTimeout(time.Duration(0))
+}
+
+// withTimeout returns a client that waits the given timeout for async jobs
+// to finish. The timeout of a client is shared by all its services, so a new
+// client is created from the provider configuration. It shares the HTTP
+// client, and with that the rate limits, and the cache of resolved IDs with
+// the given client. If the timeout is zero the client itself is returned.
+func withTimeout(cs *providerMeta, timeout time.Duration) *providerMeta {
+	if timeout <= 0 {
+		return cs
+	}
+
+	c := *cs
+	c.CloudStackClient = cs.config.newClient(cs.httpClient, int64(timeout.Seconds()))
+
+	return &c
+}
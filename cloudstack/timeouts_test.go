@@ -0,0 +1,67 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+)
+
+func TestWithTimeout(t *testing.T) {
+	s, cs := newSimulatorClient(t)
+	defer s.Close()
+
+	if c := withTimeout(cs, 0); c != cs {
+		t.Fatal("Expected a zero timeout to return the client itself")
+	}
+
+	c := withTimeout(cs, time.Second)
+	if c == cs || c.Volume == cs.Volume {
+		t.Fatal("Expected a copy of the client with its own services")
+	}
+	if c.httpClient != cs.httpClient || c.ids != cs.ids {
+		t.Fatal("Expected the copy to share the HTTP client and the ID cache")
+	}
+
+	createVolume := func(c *providerMeta) error {
+		p := c.Volume.NewCreateVolumeParams()
+		p.SetName("terraform-disk")
+		p.SetDiskofferingid(s.ID("diskoffering", "Small"))
+		p.SetZoneid(s.ID("zone", "Sandbox-simulator"))
+
+		_, err := c.Volume.CreateVolume(p)
+		return err
+	}
+
+	s.PendingPolls = 3
+
+	if err := createVolume(c); err != cloudstack.AsyncTimeoutErr {
+		t.Fatalf("Expected an async timeout error, got: %v", err)
+	}
+
+	// The timeout of the original client should not be changed
+	s.PendingPolls = 1
+
+	if err := createVolume(cs); err != nil {
+		t.Fatalf("Error creating volume: %s", err)
+	}
+}
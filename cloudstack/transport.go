@@ -0,0 +1,503 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// retryTransport is a http.RoundTripper that retries requests that fail
+// with a transient error, using an exponential backoff with jitter.
+//
+// Only read-only API calls are retried after an error or response that
+// leaves it unknown whether the call was executed. Other calls are only
+// retried when they were never sent, or when CloudStack rejected them
+// without executing them.
+type retryTransport struct {
+	transport http.RoundTripper
+
+	// maxAttempts is the maximum number of attempts for a single request
+	maxAttempts int
+
+	// timeout is the deadline of a single attempt, including reading the
+	// body of the response
+	timeout time.Duration
+
+	// waitMin and waitMax bound the delay between two attempts
+	waitMin time.Duration
+	waitMax time.Duration
+
+	// errorCodes are the CloudStack error codes that are retried in
+	// addition to HTTP 5xx responses
+	errorCodes []int
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	params, err := requestParams(req)
+	if err != nil {
+		return nil, err
+	}
+	readOnly := isReadOnlyCommand(params.Get("command"))
+
+	// The body of the request can only be sent again if it can be copied
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.attempt(req, attempt)
+
+		if attempt >= t.maxAttempts || !replayable || req.Context().Err() != nil ||
+			!t.retryable(readOnly, resp, err) {
+			return resp, err
+		}
+
+		if err != nil {
+			log.Printf("[DEBUG] Retrying CloudStack API request after error: %s", err)
+		} else {
+			log.Printf("[DEBUG] Retrying CloudStack API request after HTTP status %d", resp.StatusCode)
+
+			// Drain and close the body so the connection can be reused
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-time.After(t.backoff(attempt)):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// attempt sends a copy of the request, bounded by the timeout of a single
+// attempt. Every attempt after the first one gets a fresh copy of the body.
+// The deadline is released once the body of the response is closed.
+func (t *retryTransport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+	}
+
+	r := req.Clone(ctx)
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		r.Body = body
+	}
+
+	resp, err := t.transport.RoundTrip(r)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: cancel}
+
+	return resp, nil
+}
+
+// retryable returns true if the response or error of a request is transient
+// and the request can safely be sent again.
+func (t *retryTransport) retryable(readOnly bool, resp *http.Response, err error) bool {
+	if err != nil {
+		// The request never reached the API
+		if errors.Is(err, syscall.ECONNREFUSED) || isDialError(err) {
+			return true
+		}
+
+		return readOnly && (errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, io.ErrUnexpectedEOF) ||
+			errors.Is(err, io.EOF) ||
+			isTimeout(err))
+	}
+
+	// CloudStack uses the error code as the HTTP status of the response,
+	// and rejects throttled requests before executing them
+	if resp.StatusCode == errorCodeAPILimitExceeded {
+		return true
+	}
+
+	for _, code := range t.errorCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+
+	return readOnly && resp.StatusCode >= 500
+}
+
+// isReadOnlyCommand returns true if the API command doesn't change any
+// state, so it can be sent again without side effects.
+func isReadOnlyCommand(command string) bool {
+	for _, prefix := range []string{"list", "query", "get"} {
+		if strings.HasPrefix(command, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the next attempt. The delay doubles with
+// every attempt, up to waitMax, and a random jitter of up to half the delay
+// is subtracted so concurrent requests don't retry in lockstep.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.waitMax
+	if attempt < 32 {
+		if d := t.waitMin << uint(attempt-1); d > 0 && d < t.waitMax {
+			delay = d
+		}
+	}
+
+	if jitter := int64(delay / 2); jitter > 0 {
+		delay -= time.Duration(rand.Int63n(jitter))
+	}
+
+	return delay
+}
+
+func isTimeout(err error) bool {
+	var nerr net.Error
+	return errors.As(err, &nerr) && nerr.Timeout()
+}
+
+func isDialError(err error) bool {
+	var oerr *net.OpError
+	return errors.As(err, &oerr) && oerr.Op == "dial"
+}
+
+// rateLimitTransport is a http.RoundTripper that limits the rate and the
+// number of concurrent requests to the CloudStack API. A single transport
+// is shared by all resources, so the limits apply to the provider as a whole.
+type rateLimitTransport struct {
+	transport http.RoundTripper
+
+	// limiter limits the rate of requests, if not nil
+	limiter *tokenBucket
+
+	// sem limits the number of concurrent requests, if not nil
+	sem chan struct{}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.limiter != nil {
+		if err := t.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.sem == nil {
+		return t.transport.RoundTrip(req)
+	}
+
+	select {
+	case t.sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		<-t.sem
+		return nil, err
+	}
+
+	// The request is done once the body of the response is closed
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: func() { <-t.sem }}
+
+	return resp, nil
+}
+
+// releaseBody calls release once when the body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close implements the io.Closer interface.
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// tokenBucket is a token bucket rate limiter. The bucket holds up to burst
+// tokens and is refilled with rate tokens per second.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	burst := math.Max(1, math.Ceil(rate))
+	return &tokenBucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or the context is done. Tokens are
+// reserved in the order wait is called, so waiting callers are served in a
+// first come, first served order.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		// Return the reserved token
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// redactedParams are the parameters of API calls that hold credentials or
+// other secrets, and are never logged. Parameters containing "password" are
+// redacted as well. Certificates are public, so only their private key is
+// redacted.
+var redactedParams = map[string]bool{
+	"apikey":     true,
+	"ipsecpsk":   true,
+	"privatekey": true,
+	"secretkey":  true,
+	"signature":  true,
+	"userdata":   true,
+}
+
+// apiLogEntry is a logged API call, as written to the API log file.
+type apiLogEntry struct {
+	Time      time.Time         `json:"time"`
+	Request   uint64            `json:"request"`
+	Command   string            `json:"command"`
+	Params    map[string]string `json:"params"`
+	Status    int               `json:"status,omitempty"`
+	Duration  float64           `json:"duration_ms"`
+	JobID     string            `json:"job_id,omitempty"`
+	JobStatus *int              `json:"job_status,omitempty"`
+	ErrorText string            `json:"error,omitempty"`
+}
+
+// logTransport is a http.RoundTripper that logs the command, parameters,
+// status, latency and async job ID of every API call, with secrets redacted.
+// When out is set, the calls are also written to it as JSON lines.
+type logTransport struct {
+	transport http.RoundTripper
+
+	// requests numbers the logged calls, so the attempts of a retried
+	// call can be told apart
+	requests uint64
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	params, err := requestParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := apiLogEntry{
+		Time:    time.Now(),
+		Request: atomic.AddUint64(&t.requests, 1),
+		Command: params.Get("command"),
+		Params:  redactParams(params),
+		JobID:   params.Get("jobid"),
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	entry.Duration = float64(time.Since(entry.Time)) / float64(time.Millisecond)
+
+	if err != nil {
+		entry.ErrorText = err.Error()
+	} else {
+		entry.Status = resp.StatusCode
+
+		body, rerr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		if r := responseResult(body); r != nil {
+			if entry.JobID == "" {
+				entry.JobID = r.JobID
+			}
+			entry.JobStatus = r.JobStatus
+			entry.ErrorText = r.ErrorText
+			if entry.ErrorText == "" && r.JobResult != nil {
+				entry.ErrorText = r.JobResult.ErrorText
+			}
+		}
+	}
+
+	t.log(&entry)
+
+	return resp, err
+}
+
+func (t *logTransport) log(entry *apiLogEntry) {
+	var params []string
+	for k, v := range entry.Params {
+		params = append(params, k+"="+v)
+	}
+	sort.Strings(params)
+
+	job := entry.JobID
+	if entry.JobStatus != nil {
+		job = fmt.Sprintf("%s (status %d)", job, *entry.JobStatus)
+	}
+
+	log.Printf("[DEBUG] CloudStack API call #%d: command=%s status=%d duration=%.1fms job=%s error=%q params=%s",
+		entry.Request, entry.Command, entry.Status, entry.Duration, job, entry.ErrorText, strings.Join(params, "&"))
+
+	if t.out == nil {
+		return
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[WARN] Error encoding API log entry: %s", err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, err := t.out.Write(append(b, '\n')); err != nil {
+		log.Printf("[WARN] Error writing API log entry: %s", err)
+	}
+}
+
+// requestParams returns the parameters of an API call, which are either
+// sent in the query of a GET request or as the form body of a POST request.
+// The body is read from a copy, so the request itself is left untouched.
+func requestParams(req *http.Request) (url.Values, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return req.URL.Query(), nil
+	}
+
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := url.ParseQuery(string(body))
+	if err != nil {
+		// Log the query instead of failing the request
+		return req.URL.Query(), nil
+	}
+
+	return params, nil
+}
+
+// redactParams returns the parameters of an API call with all secrets
+// redacted, except for the command which is logged separately.
+func redactParams(params url.Values) map[string]string {
+	redacted := make(map[string]string, len(params))
+	for k, v := range params {
+		key := strings.ToLower(k)
+		switch {
+		case key == "command":
+			continue
+		case redactedParams[key] || strings.Contains(key, "password"):
+			redacted[k] = "REDACTED"
+		default:
+			redacted[k] = strings.Join(v, ",")
+		}
+	}
+	return redacted
+}
+
+type apiResult struct {
+	JobID     string `json:"jobid"`
+	JobStatus *int   `json:"jobstatus"`
+	JobResult *struct {
+		ErrorText string `json:"errortext"`
+	} `json:"jobresult"`
+	ErrorText string `json:"errortext"`
+}
+
+// responseResult returns the async job ID, job status and error text of the
+// response of an API call, which is wrapped in an object named after the
+// command.
+func responseResult(body []byte) *apiResult {
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(body, &wrapper); err != nil || len(wrapper) != 1 {
+		return nil
+	}
+
+	for _, raw := range wrapper {
+		var r apiResult
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil
+		}
+		return &r
+	}
+
+	return nil
+}
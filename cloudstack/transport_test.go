@@ -0,0 +1,451 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	cases := []struct {
+		Command    string
+		Failures   int
+		Status     int
+		ErrorCodes []int
+		Calls      int32
+		Result     int
+	}{
+		// HTTP 5xx responses are retried
+		{Command: "listZones", Failures: 2, Status: 530, Calls: 3, Result: http.StatusOK},
+		{Command: "listZones", Failures: 1, Status: http.StatusServiceUnavailable, Calls: 2, Result: http.StatusOK},
+		{Command: "queryAsyncJobResult", Failures: 1, Status: 530, Calls: 2, Result: http.StatusOK},
+		// Throttled requests are retried
+		{Command: "listZones", Failures: 1, Status: 429, Calls: 2, Result: http.StatusOK},
+		// The number of attempts is bounded
+		{Command: "listZones", Failures: 5, Status: 530, Calls: 3, Result: 530},
+		// Other errors are only retried if their code is configured
+		{Command: "listZones", Failures: 1, Status: 431, Calls: 1, Result: 431},
+		{Command: "listZones", Failures: 1, Status: 431, ErrorCodes: []int{431}, Calls: 2, Result: http.StatusOK},
+		// Calls that change state are not retried after a HTTP 5xx response,
+		// as they might have been executed
+		{Command: "deployVirtualMachine", Failures: 1, Status: 530, Calls: 1, Result: 530},
+		{Command: "createSnapshot", Failures: 1, Status: http.StatusServiceUnavailable, Calls: 1, Result: http.StatusServiceUnavailable},
+		// But they are retried if they were rejected
+		{Command: "deployVirtualMachine", Failures: 1, Status: 429, Calls: 2, Result: http.StatusOK},
+		{Command: "deployVirtualMachine", Failures: 1, Status: 431, ErrorCodes: []int{431}, Calls: 2, Result: http.StatusOK},
+	}
+
+	for i, tc := range cases {
+		var calls int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != "command="+tc.Command {
+				t.Errorf("%d: bad request body: %q", i, body)
+			}
+
+			if atomic.AddInt32(&calls, 1) <= int32(tc.Failures) {
+				w.WriteHeader(tc.Status)
+			}
+		}))
+
+		client := &http.Client{Transport: &retryTransport{
+			transport:   http.DefaultTransport,
+			maxAttempts: 3,
+			waitMin:     time.Millisecond,
+			waitMax:     10 * time.Millisecond,
+			errorCodes:  tc.ErrorCodes,
+		}}
+
+		resp, err := client.Post(srv.URL, "application/x-www-form-urlencoded", strings.NewReader("command="+tc.Command))
+		srv.Close()
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %s", i, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != tc.Result {
+			t.Fatalf("%d: bad status: %d", i, resp.StatusCode)
+		}
+		if calls != tc.Calls {
+			t.Fatalf("%d: expected %d calls, got: %d", i, tc.Calls, calls)
+		}
+	}
+}
+
+func TestRetryTransport_connectionErrors(t *testing.T) {
+	cases := []struct {
+		Command string
+		Calls   int32
+	}{
+		// A dropped connection is only retried for read-only calls
+		{Command: "listVirtualMachines", Calls: 3},
+		{Command: "deployVirtualMachine", Calls: 1},
+	}
+
+	for _, tc := range cases {
+		var calls int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Error hijacking connection: %s", err)
+				return
+			}
+			conn.Close()
+		}))
+
+		client := &http.Client{Transport: &retryTransport{
+			transport:   &http.Transport{DisableKeepAlives: true},
+			maxAttempts: 3,
+			waitMin:     time.Millisecond,
+			waitMax:     10 * time.Millisecond,
+		}}
+
+		_, err := client.PostForm(srv.URL, url.Values{"command": {tc.Command}})
+		srv.Close()
+		if err == nil {
+			t.Fatalf("%s: expected an error", tc.Command)
+		}
+
+		if calls != tc.Calls {
+			t.Fatalf("%s: expected %d calls, got: %d", tc.Command, tc.Calls, calls)
+		}
+	}
+
+	// A refused connection was never sent, so it is retried for all calls
+	srv := httptest.NewServer(http.NotFoundHandler())
+	addr := srv.URL
+	srv.Close()
+
+	var dials int32
+	client := &http.Client{Transport: &retryTransport{
+		transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				atomic.AddInt32(&dials, 1)
+				return (&net.Dialer{}).DialContext(ctx, network, address)
+			},
+		},
+		maxAttempts: 3,
+		waitMin:     time.Millisecond,
+		waitMax:     10 * time.Millisecond,
+	}}
+
+	if _, err := client.PostForm(addr, url.Values{"command": {"deployVirtualMachine"}}); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if dials != 3 {
+		t.Fatalf("Expected 3 dials, got: %d", dials)
+	}
+}
+
+func TestRetryTransport_timeout(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// Stall while sending the body of the response
+			w.Write([]byte("{"))
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &retryTransport{
+		transport:   http.DefaultTransport,
+		maxAttempts: 3,
+		timeout:     100 * time.Millisecond,
+		waitMin:     time.Millisecond,
+		waitMax:     10 * time.Millisecond,
+	}}
+
+	resp, err := client.PostForm(srv.URL, url.Values{"command": {"listZones"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	// The deadline applies to reading the body as well
+	start := time.Now()
+	if _, err := ioutil.ReadAll(resp.Body); err == nil {
+		t.Fatal("Expected the stalled body to time out")
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Fatalf("Reading the stalled body took %s", d)
+	}
+}
+
+func TestRetryTransport_request(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	tr := &retryTransport{
+		transport:   http.DefaultTransport,
+		maxAttempts: 3,
+		timeout:     time.Minute,
+		waitMin:     time.Millisecond,
+		waitMax:     10 * time.Millisecond,
+	}
+
+	body := strings.NewReader("command=listZones")
+	req, err := http.NewRequest("POST", srv.URL, body)
+	if err != nil {
+		t.Fatalf("Error creating request: %s", err)
+	}
+	ctx := req.Context()
+
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	// The request is sent as is the first time, and copied for every retry
+	if req.Context() != ctx {
+		t.Fatal("Expected the context of the request to be left untouched")
+	}
+	if body.Len() != 0 {
+		t.Fatalf("Expected the body of the request to be sent, %d bytes left", body.Len())
+	}
+}
+
+func TestRetryTransport_backoff(t *testing.T) {
+	tr := &retryTransport{waitMin: time.Second, waitMax: 30 * time.Second}
+
+	cases := []struct {
+		Attempt int
+		Max     time.Duration
+	}{
+		{Attempt: 1, Max: time.Second},
+		{Attempt: 2, Max: 2 * time.Second},
+		{Attempt: 4, Max: 8 * time.Second},
+		{Attempt: 6, Max: 30 * time.Second},
+		{Attempt: 64, Max: 30 * time.Second},
+	}
+
+	for _, tc := range cases {
+		for i := 0; i < 10; i++ {
+			if d := tr.backoff(tc.Attempt); d <= tc.Max/2 || d > tc.Max {
+				t.Fatalf("Bad delay for attempt %d: %s", tc.Attempt, d)
+			}
+		}
+	}
+}
+
+func TestRetryTransport_client(t *testing.T) {
+	s := newSimulator()
+	defer s.Close()
+
+	cfg := Config{
+		APIURL:           s.APIURL(),
+		APIKey:           simulatorAPIKey,
+		SecretKey:        simulatorSecretKey,
+		Timeout:          60,
+		RetryMaxAttempts: 3,
+		RetryWaitMin:     time.Millisecond,
+		RetryWaitMax:     10 * time.Millisecond,
+	}
+
+	cs, err := cfg.NewClient()
+	if err != nil {
+		t.Fatalf("Error creating client: %s", err)
+	}
+
+	s.FailNext("listZones", 530, 4250, "Internal error executing command")
+	s.FailNext("listZones", 530, 4250, "Internal error executing command")
+
+	if _, _, err := cs.Zone.GetZoneID("Sandbox-simulator"); err != nil {
+		t.Fatalf("Expected the call to be retried, got: %s", err)
+	}
+
+	if calls := s.Calls("listZones"); calls != 3 {
+		t.Fatalf("Expected 3 calls, got: %d", calls)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket(20)
+
+	// The burst is available immediately, the remaining requests are
+	// spread at the configured rate
+	start := time.Now()
+	for i := 0; i < 30; i++ {
+		if err := b.wait(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond || elapsed > 2*time.Second {
+		t.Fatalf("Expected 30 requests at 20/s with a burst of 20 to take 0.5s, took: %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b = newTokenBucket(0.1)
+	b.wait(ctx)
+	if err := b.wait(ctx); err != context.Canceled {
+		t.Fatalf("Expected the wait to be canceled, got: %v", err)
+	}
+}
+
+func TestRateLimitTransport_concurrency(t *testing.T) {
+	var active, max int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &rateLimitTransport{
+		transport: http.DefaultTransport,
+		sem:       make(chan struct{}, 2),
+	}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if max > 2 {
+		t.Fatalf("Expected at most 2 concurrent requests, got: %d", max)
+	}
+	if len(client.Transport.(*rateLimitTransport).sem) != 0 {
+		t.Fatal("Expected all requests to be released")
+	}
+}
+
+func TestLogTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("signature") != "c2lnbmF0dXJl" {
+			t.Errorf("Expected the request to be sent unmodified, got: %v", r.Form)
+		}
+
+		switch r.Form.Get("command") {
+		case "deployVirtualMachine":
+			w.Write([]byte(`{"deployvirtualmachineresponse":{"id":"vm-1","jobid":"job-1"}}`))
+		case "queryAsyncJobResult":
+			w.Write([]byte(`{"queryasyncjobresultresponse":{"jobid":"job-1","jobstatus":2,` +
+				`"jobresult":{"errorcode":530,"errortext":"Insufficient capacity"}}}`))
+		}
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	client := &http.Client{Transport: &logTransport{transport: http.DefaultTransport, out: &out}}
+
+	form := url.Values{
+		"command":    {"deployVirtualMachine"},
+		"name":       {"web"},
+		"apiKey":     {"key"},
+		"signature":  {"c2lnbmF0dXJl"},
+		"userdata":   {"c2VjcmV0"},
+		"password":   {"secret"},
+		"privateKey": {"secret-key"},
+	}
+	resp, err := client.PostForm(srv.URL, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "job-1") {
+		t.Fatalf("Expected the response body to be passed on, got: %s", body)
+	}
+
+	resp, err = client.Get(srv.URL + "?command=queryAsyncJobResult&jobid=job-1&signature=c2lnbmF0dXJl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 logged calls, got: %q", out.String())
+	}
+	if strings.Contains(out.String(), "secret") || strings.Contains(out.String(), "c2lnbmF0dXJl") {
+		t.Fatalf("Expected secrets to be redacted, got: %s", out.String())
+	}
+
+	var deploy, query apiLogEntry
+	if err := json.Unmarshal([]byte(lines[0]), &deploy); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &query); err != nil {
+		t.Fatal(err)
+	}
+
+	if deploy.Command != "deployVirtualMachine" || deploy.Status != http.StatusOK || deploy.JobID != "job-1" {
+		t.Fatalf("Bad log entry: %+v", deploy)
+	}
+	for _, k := range []string{"apiKey", "signature", "userdata", "password", "privateKey"} {
+		if deploy.Params[k] != "REDACTED" {
+			t.Fatalf("Expected %s to be redacted, got: %q", k, deploy.Params[k])
+		}
+	}
+	if deploy.Params["name"] != "web" {
+		t.Fatalf("Expected name to be logged, got: %q", deploy.Params["name"])
+	}
+
+	if query.Command != "queryAsyncJobResult" || query.JobID != "job-1" ||
+		query.JobStatus == nil || *query.JobStatus != 2 || query.ErrorText != "Insufficient capacity" {
+		t.Fatalf("Bad log entry: %+v", query)
+	}
+	if query.Request != deploy.Request+1 {
+		t.Fatalf("Expected calls to be numbered, got: %d and %d", deploy.Request, query.Request)
+	}
+}
@@ -0,0 +1,152 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+var (
+	// affinityGroupTypes are the affinity group types supported by CloudStack
+	affinityGroupTypes = []string{
+		"host affinity",
+		"host anti-affinity",
+		"non-strict host affinity",
+		"non-strict host anti-affinity",
+		"ExplicitDedication",
+	}
+
+	// loadBalancerAlgorithms are the algorithms of the load balancer rules
+	loadBalancerAlgorithms = []string{"roundrobin", "leastconn", "source"}
+
+	// loadBalancerProtocols are the protocols of the load balancer rules
+	loadBalancerProtocols = []string{"tcp", "udp", "tcp-proxy", "ssl"}
+
+	// trafficTypes are the traffic types of network ACL and security group rules
+	trafficTypes = []string{"ingress", "egress"}
+)
+
+// validateCIDR validates a CIDR block, like 10.0.0.0/8.
+func validateCIDR(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if _, _, err := net.ParseCIDR(value); err != nil {
+		es = append(es, fmt.Errorf("%q must be a valid CIDR, got: %s", k, value))
+	}
+	return
+}
+
+// validateCIDRList validates a comma separated list of CIDR blocks.
+func validateCIDRList(v interface{}, k string) (ws []string, es []error) {
+	for _, cidr := range strings.Split(v.(string), ",") {
+		w, e := validateCIDR(strings.TrimSpace(cidr), k)
+		ws = append(ws, w...)
+		es = append(es, e...)
+	}
+	return
+}
+
+// validateIPAddress validates a single IPv4 or IPv6 address.
+func validateIPAddress(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if net.ParseIP(value) == nil {
+		es = append(es, fmt.Errorf("%q must be a valid IP address, got: %s", k, value))
+	}
+	return
+}
+
+// validatePortRange validates a single port (80) or a port range (80-90).
+func validatePortRange(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+
+	m := splitPorts.FindStringSubmatch(value)
+	if m == nil {
+		es = append(es, fmt.Errorf(
+			"%q must be a port or a port range like '80' or '80-90', got: %s", k, value))
+		return
+	}
+
+	startPort, _ := strconv.Atoi(m[1])
+	endPort := startPort
+	if m[2] != "" {
+		endPort, _ = strconv.Atoi(m[2])
+	}
+
+	if startPort < 1 || endPort > 65535 || startPort > endPort {
+		es = append(es, fmt.Errorf(
+			"%q must be a port range between 1 and 65535 with the start port "+
+				"lower than the end port, got: %s", k, value))
+	}
+
+	return
+}
+
+// validatePort validates a single port number.
+var validatePort = validation.IntBetween(1, 65535)
+
+// validateICMP validates an ICMP type or code, where -1 means all types or codes.
+var validateICMP = validation.IntBetween(-1, 255)
+
+// validateProtocol returns a SchemaValidateFunc that accepts the given
+// protocol names, or a protocol number between 0 and 255.
+func validateProtocol(protocols ...string) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, es []error) {
+		value := v.(string)
+
+		for _, p := range protocols {
+			if value == p {
+				return
+			}
+		}
+
+		if n, err := strconv.ParseInt(value, 0, 0); err == nil && n >= 0 && n <= 255 {
+			return
+		}
+
+		es = append(es, fmt.Errorf(
+			"%q must be one of '%s' or a valid protocol number, got: %s",
+			k, strings.Join(protocols, "', '"), value))
+
+		return
+	}
+}
+
+// validateDuration validates a positive duration, like 30s or 5m.
+func validateDuration(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		es = append(es, fmt.Errorf("%q must be a valid duration like '30s' or '5m', got: %s", k, value))
+		return
+	}
+
+	if d < 0 {
+		es = append(es, fmt.Errorf("%q must be a positive duration, got: %s", k, value))
+	}
+
+	return
+}
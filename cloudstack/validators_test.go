@@ -0,0 +1,70 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudstack
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestValidators(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Validator schema.SchemaValidateFunc
+		Value     interface{}
+		Valid     bool
+	}{
+		{"cidr", validateCIDR, "10.0.0.0/8", true},
+		{"cidr", validateCIDR, "2001:db8::/32", true},
+		{"cidr", validateCIDR, "10.0.0.0/33", false},
+		{"cidr", validateCIDR, "10.0.0.0", false},
+		{"cidr list", validateCIDRList, "10.0.0.0/8, 192.168.0.0/16", true},
+		{"cidr list", validateCIDRList, "10.0.0.0/8,192.168.0.0", false},
+		{"ip", validateIPAddress, "10.0.0.1", true},
+		{"ip", validateIPAddress, "2001:db8::1", true},
+		{"ip", validateIPAddress, "10.0.0.256", false},
+		{"ports", validatePortRange, "80", true},
+		{"ports", validatePortRange, "80-90", true},
+		{"ports", validatePortRange, "80-", false},
+		{"ports", validatePortRange, "90-80", false},
+		{"ports", validatePortRange, "0", false},
+		{"ports", validatePortRange, "1-65536", false},
+		{"port", validatePort, 443, true},
+		{"port", validatePort, 65536, false},
+		{"icmp", validateICMP, -1, true},
+		{"icmp", validateICMP, 256, false},
+		{"protocol", validateProtocol("tcp", "udp"), "tcp", true},
+		{"protocol", validateProtocol("tcp", "udp"), "41", true},
+		{"protocol", validateProtocol("tcp", "udp"), "tcpp", false},
+		{"protocol", validateProtocol("tcp", "udp"), "256", false},
+		{"duration", validateDuration, "30s", true},
+		{"duration", validateDuration, "1h30m", true},
+		{"duration", validateDuration, "30", false},
+		{"duration", validateDuration, "-5m", false},
+	}
+
+	for _, tc := range cases {
+		_, es := tc.Validator(tc.Value, tc.Name)
+		if valid := len(es) == 0; valid != tc.Valid {
+			t.Errorf("%s %v: expected valid to be %t, got errors: %v", tc.Name, tc.Value, tc.Valid, es)
+		}
+	}
+}